@@ -0,0 +1,301 @@
+// Package auth authenticates callers of the HTTP transport with static tokens or JWTs.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/config"
+)
+
+const (
+	// sessionIDHeader is the header carrying the MCP session ID of the streamable HTTP transport.
+	sessionIDHeader = "Mcp-Session-Id"
+
+	// sessionIdleTimeout is how long an MCP session stays bound to its caller without requests. The
+	// streamable HTTP handler only ends sessions on DELETE, so clients that go away without one would
+	// otherwise leave their binding behind forever.
+	sessionIdleTimeout = time.Hour
+)
+
+// Authentication errors.
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Identity describes an authenticated caller.
+type Identity struct {
+	Subject string // token name or JWT subject
+	Method  string // token or jwt
+}
+
+type identityKey struct{}
+
+// ContextWithIdentity returns a context carrying the caller identity.
+func ContextWithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the caller identity of ctx, if any.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// Authenticator authenticates requests with a bearer token in the Authorization header or an API key
+// in the X-API-Key header. Credentials are checked against the static tokens first and then, if
+// configured, validated as JWTs.
+type Authenticator struct {
+	logger *slog.Logger
+	tokens map[[sha256.Size]byte]string // token hashes to caller names
+	jwt    *JWTVerifier
+
+	mu       sync.Mutex
+	sessions map[string]*boundSession // MCP session IDs to the caller that created them
+	now      func() time.Time
+}
+
+// boundSession is an MCP session bound to the caller that created it. The caller is identified by
+// both subject and method, as a token name may equal the subject of a JWT.
+type boundSession struct {
+	identity Identity
+	lastSeen time.Time
+}
+
+// NewAuthenticator creates an Authenticator for the static tokens, mapped to their caller names, and
+// the optional JWT verifier.
+func NewAuthenticator(logger *slog.Logger, tokens map[string]string, jwt *JWTVerifier) *Authenticator {
+	hashed := make(map[[sha256.Size]byte]string, len(tokens))
+	for token, name := range tokens {
+		hashed[sha256.Sum256([]byte(token))] = name
+	}
+
+	return &Authenticator{
+		logger:   logger,
+		tokens:   hashed,
+		jwt:      jwt,
+		sessions: make(map[string]*boundSession),
+		now:      time.Now,
+	}
+}
+
+// New creates the Authenticator described by the configuration. It returns nil when no static tokens
+// or JWKS file are configured.
+func New(cfg *config.Config, logger *slog.Logger) (*Authenticator, error) {
+	if cfg.AuthTokens == "" && cfg.AuthTokensFile == "" && cfg.AuthJWKSFile == "" {
+		return nil, nil //nolint:nilnil // a nil authenticator disables authentication
+	}
+
+	tokens, err := ParseTokens(cfg.AuthTokens)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.AuthTokensFile != "" {
+		fileTokens, loadErr := LoadTokens(cfg.AuthTokensFile)
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		maps.Copy(tokens, fileTokens)
+	}
+
+	var verifier *JWTVerifier
+	if cfg.AuthJWKSFile != "" {
+		verifier, err = NewJWTVerifier(cfg.AuthJWKSFile, cfg.AuthJWTIssuer, cfg.AuthJWTAudience)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewAuthenticator(logger, tokens, verifier), nil
+}
+
+// Authenticate returns the identity of the caller making the request.
+func (a *Authenticator) Authenticate(r *http.Request) (Identity, error) {
+	credential := r.Header.Get("X-API-Key")
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		credential = strings.TrimSpace(token)
+	}
+	if credential == "" {
+		return Identity{}, ErrMissingCredentials
+	}
+
+	if name, ok := a.tokens[sha256.Sum256([]byte(credential))]; ok {
+		return Identity{Subject: name, Method: "token"}, nil
+	}
+
+	if a.jwt != nil && strings.Count(credential, ".") == 2 {
+		subject, err := a.jwt.Verify(credential)
+		if err != nil {
+			return Identity{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+		}
+		return Identity{Subject: subject, Method: "jwt"}, nil
+	}
+
+	return Identity{}, ErrInvalidCredentials
+}
+
+// Middleware rejects unauthenticated requests with 401 and passes the caller identity on to next in the
+// request context. MCP sessions are bound to the caller that created them, so that a session ID cannot
+// be used by another caller. Sessions idle for longer than an hour are forgotten and answered with 404,
+// which tells the client to start a new session.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, err := a.Authenticate(r)
+		if err != nil {
+			a.logger.WarnContext(r.Context(), "Rejected unauthenticated request",
+				"method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr, "error", err)
+			challenge := `Bearer realm="mcp"`
+			if !errors.Is(err, ErrMissingCredentials) {
+				challenge += `, error="invalid_token"`
+			}
+			w.Header().Set("WWW-Authenticate", challenge)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		sessionID := r.Header.Get(sessionIDHeader)
+		if sessionID != "" {
+			known, owned := a.useSession(sessionID, identity)
+			if !known {
+				http.Error(w, "session not found", http.StatusNotFound)
+				return
+			}
+			if !owned {
+				a.logger.WarnContext(r.Context(), "Rejected request for another caller's session",
+					"caller", identity.Subject, "auth_method", identity.Method, "remote_addr", r.RemoteAddr)
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+		}
+
+		a.logger.DebugContext(r.Context(), "Authenticated request",
+			"caller", identity.Subject, "auth_method", identity.Method, "method", r.Method, "path", r.URL.Path)
+		r = r.WithContext(ContextWithIdentity(r.Context(), identity))
+
+		switch {
+		case sessionID == "":
+			next.ServeHTTP(&sessionRecorder{ResponseWriter: w, authenticator: a, identity: identity}, r)
+		case r.Method == http.MethodDelete:
+			next.ServeHTTP(w, r)
+			a.mu.Lock()
+			delete(a.sessions, sessionID)
+			a.mu.Unlock()
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// useSession reports whether the session is known and not idle for too long, and whether it was
+// created by the caller, in which case it counts as used now.
+func (a *Authenticator) useSession(sessionID string, identity Identity) (bool, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	session, ok := a.sessions[sessionID]
+	if !ok || now.Sub(session.lastSeen) > sessionIdleTimeout {
+		delete(a.sessions, sessionID)
+		return false, false
+	}
+	if session.identity != identity {
+		return true, false
+	}
+	session.lastSeen = now
+	return true, true
+}
+
+// bindSession binds a new session to the caller and forgets the sessions that have been idle for too
+// long.
+func (a *Authenticator) bindSession(sessionID string, identity Identity) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	for id, session := range a.sessions {
+		if now.Sub(session.lastSeen) > sessionIdleTimeout {
+			delete(a.sessions, id)
+		}
+	}
+	a.sessions[sessionID] = &boundSession{identity: identity, lastSeen: now}
+}
+
+// sessionRecorder records the owner of the MCP session that the response creates, if any.
+type sessionRecorder struct {
+	http.ResponseWriter
+	authenticator *Authenticator
+	identity      Identity
+	recorded      bool
+}
+
+func (w *sessionRecorder) record() {
+	if w.recorded {
+		return
+	}
+	w.recorded = true
+
+	if sessionID := w.Header().Get(sessionIDHeader); sessionID != "" {
+		w.authenticator.bindSession(sessionID, w.identity)
+	}
+}
+
+func (w *sessionRecorder) WriteHeader(statusCode int) {
+	w.record()
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *sessionRecorder) Write(b []byte) (int, error) {
+	w.record()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush flushes the streamed events of the MCP handler.
+func (w *sessionRecorder) Flush() {
+	w.record()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *sessionRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// LogHandler adds the caller identity of the context to log records as the caller attribute.
+type LogHandler struct {
+	slog.Handler
+}
+
+// NewLogHandler wraps handler with a LogHandler.
+func NewLogHandler(handler slog.Handler) *LogHandler {
+	return &LogHandler{Handler: handler}
+}
+
+// Handle adds the caller to the record and passes it on.
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if identity, ok := IdentityFromContext(ctx); ok {
+		record = record.Clone()
+		record.AddAttrs(slog.String("caller", identity.Subject))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a LogHandler whose wrapped handler has the attributes.
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a LogHandler whose wrapped handler has the group.
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{Handler: h.Handler.WithGroup(name)}
+}
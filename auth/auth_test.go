@@ -0,0 +1,297 @@
+package auth_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/auth"
+	"github.com/rameshsunkara/go-mcp-example/config"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// identityHandler responds with the caller identity of the request, and creates a session when the
+// request has none, like the MCP handler.
+func identityHandler(sessionID string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := auth.IdentityFromContext(r.Context())
+		if !ok {
+			http.Error(w, "no identity", http.StatusInternalServerError)
+			return
+		}
+		if r.Header.Get("Mcp-Session-Id") == "" {
+			w.Header().Set("Mcp-Session-Id", sessionID)
+		}
+		_, _ = io.WriteString(w, identity.Method+":"+identity.Subject)
+	})
+}
+
+func newTestAuthenticator(t *testing.T) *auth.Authenticator {
+	t.Helper()
+	return auth.NewAuthenticator(discardLogger(),
+		map[string]string{"0123456789abcdef": "ci", "fedcba9876543210": "alice"},
+		newVerifier(t, "", ""))
+}
+
+func TestAuthenticator_Middleware(t *testing.T) {
+	t.Parallel()
+
+	testKeys(t)
+	tests := []struct {
+		name      string
+		header    string
+		value     string
+		status    int
+		body      string
+		challenge string
+	}{
+		{
+			name:      "no credentials",
+			status:    http.StatusUnauthorized,
+			challenge: `Bearer realm="mcp"`,
+		},
+		{
+			name:      "unknown token",
+			header:    "Authorization",
+			value:     "Bearer 0000000000000000",
+			status:    http.StatusUnauthorized,
+			challenge: `Bearer realm="mcp", error="invalid_token"`,
+		},
+		{
+			name:      "other scheme",
+			header:    "Authorization",
+			value:     "Basic 0123456789abcdef",
+			status:    http.StatusUnauthorized,
+			challenge: `Bearer realm="mcp"`,
+		},
+		{
+			name:      "expired JWT",
+			header:    "Authorization",
+			value:     "Bearer " + signToken(rsaKey, claims("alice", map[string]any{"exp": 1})),
+			status:    http.StatusUnauthorized,
+			challenge: `Bearer realm="mcp", error="invalid_token"`,
+		},
+		{
+			name:   "bearer token",
+			header: "Authorization",
+			value:  "Bearer 0123456789abcdef",
+			status: http.StatusOK,
+			body:   "token:ci",
+		},
+		{
+			name:   "lowercase scheme",
+			header: "Authorization",
+			value:  "bearer 0123456789abcdef",
+			status: http.StatusOK,
+			body:   "token:ci",
+		},
+		{
+			name:   "API key",
+			header: "X-API-Key",
+			value:  "fedcba9876543210",
+			status: http.StatusOK,
+			body:   "token:alice",
+		},
+		{
+			name:   "JWT",
+			header: "Authorization",
+			value:  "Bearer " + signToken(ecKey, claims("bob", nil)),
+			status: http.StatusOK,
+			body:   "jwt:bob",
+		},
+	}
+
+	handler := newTestAuthenticator(t).Middleware(identityHandler("session"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("Status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tt.challenge {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, tt.challenge)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("Body = %q, want %q", rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+// newSessionServer returns a function serving a request with the token and session ID through the
+// middleware of the authenticator, which creates session-1 for requests without a session.
+func newSessionServer(authenticator *auth.Authenticator) func(method, token, sessionID string) int {
+	handler := authenticator.Middleware(identityHandler("session-1"))
+	return func(method, token, sessionID string) int {
+		req := httptest.NewRequest(method, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if sessionID != "" {
+			req.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+}
+
+func TestAuthenticator_Middleware_Sessions(t *testing.T) {
+	t.Parallel()
+
+	testKeys(t)
+	serve := newSessionServer(newTestAuthenticator(t))
+	steps := []struct {
+		name      string
+		method    string
+		token     string
+		sessionID string
+		status    int
+	}{
+		{name: "ci creates session", method: http.MethodPost, token: "0123456789abcdef", status: http.StatusOK},
+		{
+			name:      "alice uses the session of ci",
+			method:    http.MethodPost,
+			token:     "fedcba9876543210",
+			sessionID: "session-1",
+			status:    http.StatusForbidden,
+		},
+		{
+			name:      "a JWT with subject ci uses the session of the ci token",
+			method:    http.MethodPost,
+			token:     signToken(edKey, claims("ci", nil)),
+			sessionID: "session-1",
+			status:    http.StatusForbidden,
+		},
+		{
+			name:      "ci uses its session",
+			method:    http.MethodGet,
+			token:     "0123456789abcdef",
+			sessionID: "session-1",
+			status:    http.StatusOK,
+		},
+		{
+			name:      "ci ends its session",
+			method:    http.MethodDelete,
+			token:     "0123456789abcdef",
+			sessionID: "session-1",
+			status:    http.StatusOK,
+		},
+		{
+			name:      "ended sessions are not found",
+			method:    http.MethodPost,
+			token:     "0123456789abcdef",
+			sessionID: "session-1",
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "unknown sessions are not found",
+			method:    http.MethodPost,
+			token:     "0123456789abcdef",
+			sessionID: "session-2",
+			status:    http.StatusNotFound,
+		},
+	}
+
+	for _, step := range steps {
+		if status := serve(step.method, step.token, step.sessionID); status != step.status {
+			t.Errorf("%s: status = %d, want %d", step.name, status, step.status)
+		}
+	}
+}
+
+func TestAuthenticator_Middleware_IdleSessions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC)
+	authenticator := auth.NewAuthenticator(discardLogger(), map[string]string{"0123456789abcdef": "ci"}, nil)
+	auth.SetClock(authenticator, func() time.Time { return now })
+	serve := newSessionServer(authenticator)
+
+	if status := serve(http.MethodPost, "0123456789abcdef", ""); status != http.StatusOK {
+		t.Fatalf("Creating the session: status = %d, want %d", status, http.StatusOK)
+	}
+
+	// Each request keeps the session alive for another hour
+	now = now.Add(50 * time.Minute)
+	if status := serve(http.MethodPost, "0123456789abcdef", "session-1"); status != http.StatusOK {
+		t.Errorf("Session used within the idle timeout: status = %d, want %d", status, http.StatusOK)
+	}
+	now = now.Add(50 * time.Minute)
+	if status := serve(http.MethodGet, "0123456789abcdef", "session-1"); status != http.StatusOK {
+		t.Errorf("Session used again within the idle timeout: status = %d, want %d", status, http.StatusOK)
+	}
+
+	now = now.Add(61 * time.Minute)
+	if status := serve(http.MethodPost, "0123456789abcdef", "session-1"); status != http.StatusNotFound {
+		t.Errorf("Session idle for too long: status = %d, want %d", status, http.StatusNotFound)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	authenticator, err := auth.New(&config.Config{}, discardLogger())
+	if err != nil || authenticator != nil {
+		t.Errorf("New() without tokens = %v, %v, want nil", authenticator, err)
+	}
+
+	cfg := &config.Config{
+		AuthTokens:     "ci:0123456789abcdef",
+		AuthTokensFile: writeFile(t, "tokens", []byte("alice:fedcba9876543210\n")),
+		AuthJWKSFile:   writeFile(t, "jwks.json", testKeys(t)),
+	}
+	authenticator, err = auth.New(cfg, discardLogger())
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	for _, token := range []string{"0123456789abcdef", "fedcba9876543210", signToken(edKey, claims("carol", nil))} {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if _, err = authenticator.Authenticate(req); err != nil {
+			t.Errorf("Authenticate() unexpected error: %v", err)
+		}
+	}
+
+	cfg.AuthTokens = "ci:short"
+	if _, err = auth.New(cfg, discardLogger()); err == nil {
+		t.Error("New() with an invalid token expected error but got none")
+	}
+}
+
+func TestLogHandler(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	logger := slog.New(auth.NewLogHandler(slog.NewJSONHandler(&out, nil))).With("component", "tools")
+
+	ctx := auth.ContextWithIdentity(context.Background(), auth.Identity{Subject: "ci", Method: "token"})
+	logger.InfoContext(ctx, "Processing get_report tool call")
+	logger.InfoContext(context.Background(), "Realtime poller started")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Logged %d lines, want 2: %s", len(lines), out.String())
+	}
+	if !strings.Contains(lines[0], `"component":"tools","caller":"ci"`) {
+		t.Errorf("Log line is missing the caller: %s", lines[0])
+	}
+	if strings.Contains(lines[1], "caller") {
+		t.Errorf("Log line without an identity has a caller: %s", lines[1])
+	}
+}
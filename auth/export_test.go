@@ -0,0 +1,8 @@
+package auth
+
+import "time"
+
+// SetClock sets the clock against which the authenticator expires idle sessions.
+func SetClock(a *Authenticator, now func() time.Time) {
+	a.now = now
+}
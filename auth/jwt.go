@@ -0,0 +1,359 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers the SHA-256 hash for RS256, PS256 and ES256
+	_ "crypto/sha512" // registers the SHA-384 and SHA-512 hashes
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// clockSkew is the leeway allowed when checking the exp and nbf claims.
+	clockSkew = time.Minute
+	// minRSAKeyBits is the size of the smallest RSA key accepted.
+	minRSAKeyBits = 2048
+	// uncompressedPoint prefixes the SEC 1 encoding of an uncompressed elliptic curve point.
+	uncompressedPoint = 0x04
+)
+
+// signingAlgorithm describes a supported JWS algorithm.
+type signingAlgorithm struct {
+	hash  crypto.Hash
+	kty   string         // JWK key type
+	curve elliptic.Curve // for ECDSA
+	pss   bool           // RSASSA-PSS rather than PKCS #1 v1.5
+}
+
+// signingAlgorithms are the supported JWS algorithms. HMAC algorithms are deliberately missing, since a
+// JWKS file holds public keys.
+var signingAlgorithms = map[string]signingAlgorithm{
+	"RS256": {hash: crypto.SHA256, kty: "RSA"},
+	"RS384": {hash: crypto.SHA384, kty: "RSA"},
+	"RS512": {hash: crypto.SHA512, kty: "RSA"},
+	"PS256": {hash: crypto.SHA256, kty: "RSA", pss: true},
+	"PS384": {hash: crypto.SHA384, kty: "RSA", pss: true},
+	"PS512": {hash: crypto.SHA512, kty: "RSA", pss: true},
+	"ES256": {hash: crypto.SHA256, kty: "EC", curve: elliptic.P256()},
+	"ES384": {hash: crypto.SHA384, kty: "EC", curve: elliptic.P384()},
+	"ES512": {hash: crypto.SHA512, kty: "EC", curve: elliptic.P521()},
+	"EdDSA": {kty: "OKP"},
+}
+
+// verificationKey is a public key from the JWKS file.
+type verificationKey struct {
+	id        string
+	algorithm string // empty if the key does not restrict its algorithm
+	kty       string
+	key       crypto.PublicKey
+}
+
+// JWTVerifier validates JWTs signed with the keys of a JWKS file.
+type JWTVerifier struct {
+	keys     []verificationKey
+	issuer   string
+	audience string
+}
+
+// NewJWTVerifier creates a JWTVerifier for the keys in the JWKS file. When issuer or audience are not
+// empty, tokens must carry them in their iss and aud claims.
+func NewJWTVerifier(jwksPath, issuer, audience string) (*JWTVerifier, error) {
+	data, err := os.ReadFile(jwksPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWKS file %s: %w", jwksPath, err)
+	}
+	return &JWTVerifier{keys: keys, issuer: issuer, audience: audience}, nil
+}
+
+// jwtHeader holds the JOSE header fields of a JWT.
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// jwtClaims holds the registered claims checked by the verifier.
+type jwtClaims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *float64 `json:"exp"`
+	NotBefore *float64 `json:"nbf"`
+}
+
+// audience is the aud claim, which is either a string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+// Verify checks the signature and claims of the token and returns its subject.
+func (v *JWTVerifier) Verify(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 { //nolint:mnd // header, payload and signature
+		return "", errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", fmt.Errorf("malformed token header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("malformed token signature: %w", err)
+	}
+	if err = v.verifySignature(header, parts[0]+"."+parts[1], signature); err != nil {
+		return "", err
+	}
+
+	var claims jwtClaims
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return "", fmt.Errorf("malformed token claims: %w", err)
+	}
+	if err = v.checkClaims(claims, time.Now()); err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
+}
+
+// verifySignature checks the signature against the keys matching the header's key ID and algorithm.
+func (v *JWTVerifier) verifySignature(header jwtHeader, signingInput string, signature []byte) error {
+	algorithm, ok := signingAlgorithms[header.Algorithm]
+	if !ok {
+		return fmt.Errorf("unsupported signing algorithm '%s'", header.Algorithm)
+	}
+
+	var digest []byte
+	if algorithm.hash != 0 {
+		h := algorithm.hash.New()
+		h.Write([]byte(signingInput))
+		digest = h.Sum(nil)
+	}
+
+	matched := false
+	for _, key := range v.keys {
+		if (header.KeyID != "" && key.id != header.KeyID) || key.kty != algorithm.kty ||
+			(key.algorithm != "" && key.algorithm != header.Algorithm) {
+			continue
+		}
+		matched = true
+		if verifyWithKey(algorithm, key.key, signingInput, digest, signature) {
+			return nil
+		}
+	}
+
+	if !matched {
+		return fmt.Errorf("no %s key with ID '%s'", header.Algorithm, header.KeyID)
+	}
+	return errors.New("invalid token signature")
+}
+
+// verifyWithKey reports whether the signature was made by the key.
+func verifyWithKey(algorithm signingAlgorithm, key crypto.PublicKey, signingInput string,
+	digest, signature []byte) bool {
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if algorithm.pss {
+			opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+			return rsa.VerifyPSS(pub, algorithm.hash, digest, signature, opts) == nil
+		}
+		return rsa.VerifyPKCS1v15(pub, algorithm.hash, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		// JWS encodes ECDSA signatures as the fixed-size concatenation of r and s
+		size := coordinateSize(pub.Curve)
+		if pub.Curve != algorithm.curve || len(signature) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(pub, digest, r, s)
+	case ed25519.PublicKey:
+		return ed25519.Verify(pub, []byte(signingInput), signature)
+	default:
+		return false
+	}
+}
+
+// checkClaims checks the expiry, issuer and audience of a token at time now.
+func (v *JWTVerifier) checkClaims(claims jwtClaims, now time.Time) error {
+	switch {
+	case claims.Subject == "":
+		return errors.New("token has no subject")
+	case claims.ExpiresAt == nil:
+		return errors.New("token has no expiry")
+	case now.Add(-clockSkew).After(unixTime(*claims.ExpiresAt)):
+		return errors.New("token has expired")
+	case claims.NotBefore != nil && now.Add(clockSkew).Before(unixTime(*claims.NotBefore)):
+		return errors.New("token is not valid yet")
+	case v.issuer != "" && claims.Issuer != v.issuer:
+		return fmt.Errorf("unexpected token issuer '%s'", claims.Issuer)
+	case v.audience != "" && !slices.Contains(claims.Audience, v.audience):
+		return errors.New("token is not intended for this server")
+	}
+	return nil
+}
+
+// unixTime converts a JWT NumericDate to a time, dropping fractions of a second.
+func unixTime(seconds float64) time.Time {
+	return time.Unix(int64(seconds), 0)
+}
+
+// decodeSegment decodes a base64url-encoded JSON segment of a JWT.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// jsonWebKey holds the JWK fields of the supported key types.
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+}
+
+// parseJWKS parses the signature verification keys of a JWK set. Encryption keys and keys of
+// unsupported types are skipped.
+func parseJWKS(data []byte) ([]verificationKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	var keys []verificationKey
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch jwk.KeyType {
+		case "RSA":
+			key, err = parseRSAKey(jwk)
+		case "EC":
+			key, err = parseECKey(jwk)
+		case "OKP":
+			key, err = parseEd25519Key(jwk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key '%s': %w", jwk.KeyID, err)
+		}
+		keys = append(keys, verificationKey{id: jwk.KeyID, algorithm: jwk.Algorithm, kty: jwk.KeyType, key: key})
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no signature verification keys")
+	}
+	return keys, nil
+}
+
+func parseRSAKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() <= 1 || exponent.Int64() > math.MaxInt32 {
+		return nil, errors.New("invalid exponent")
+	}
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+	if key.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("RSA key is shorter than %d bits", minRSAKeyBits)
+	}
+	return key, nil
+}
+
+func parseECKey(jwk jsonWebKey) (*ecdsa.PublicKey, error) {
+	var (
+		curve     elliptic.Curve
+		ecdhCurve ecdh.Curve
+	)
+	switch jwk.Curve {
+	case "P-256":
+		curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+	case "P-384":
+		curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+	case "P-521":
+		curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve '%s'", jwk.Curve)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x coordinate: %w", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid y coordinate: %w", err)
+	}
+
+	// Let crypto/ecdh check that the point is on the curve
+	size := coordinateSize(curve)
+	if len(x) != size || len(y) != size {
+		return nil, errors.New("invalid coordinate length")
+	}
+	if _, err = ecdhCurve.NewPublicKey(slices.Concat([]byte{uncompressedPoint}, x, y)); err != nil {
+		return nil, err
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+}
+
+// coordinateSize returns the size in bytes of a coordinate on the curve.
+func coordinateSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8 //nolint:mnd // bits to bytes, rounded up
+}
+
+func parseEd25519Key(jwk jsonWebKey) (ed25519.PublicKey, error) {
+	if jwk.Curve != "Ed25519" {
+		return nil, fmt.Errorf("unsupported curve '%s'", jwk.Curve)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil || len(x) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid public key")
+	}
+	return ed25519.PublicKey(x), nil
+}
@@ -0,0 +1,276 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/auth"
+)
+
+// signingKey signs test tokens with one of the keys in the JWKS file of testKeys.
+type signingKey struct {
+	id        string
+	algorithm string
+	sign      func(signingInput []byte) []byte
+}
+
+var (
+	testKeysOnce sync.Once
+	testJWKS     []byte
+	rsaKey       signingKey
+	ecKey        signingKey
+	edKey        signingKey
+)
+
+// testKeys generates the RSA, ECDSA and Ed25519 signing keys once and returns the JWKS of their
+// public keys.
+func testKeys(t *testing.T) []byte {
+	t.Helper()
+	testKeysOnce.Do(func() {
+		rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			panic(err)
+		}
+		ecPrivate, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			panic(err)
+		}
+		edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			panic(err)
+		}
+
+		rsaKey = signingKey{id: "rsa", algorithm: "RS256", sign: func(input []byte) []byte {
+			digest := sha256.Sum256(input)
+			signature, _ := rsa.SignPKCS1v15(rand.Reader, rsaPrivate, crypto.SHA256, digest[:])
+			return signature
+		}}
+		ecKey = signingKey{id: "ec", algorithm: "ES256", sign: func(input []byte) []byte {
+			digest := sha256.Sum256(input)
+			r, s, _ := ecdsa.Sign(rand.Reader, ecPrivate, digest[:])
+			return append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		}}
+		edKey = signingKey{id: "ed", algorithm: "EdDSA", sign: func(input []byte) []byte {
+			return ed25519.Sign(edPrivate, input)
+		}}
+
+		encode := base64.RawURLEncoding.EncodeToString
+		testJWKS, _ = json.Marshal(map[string]any{"keys": []map[string]string{
+			{
+				"kty": "RSA", "kid": "rsa", "use": "sig", "alg": "RS256",
+				"n": encode(rsaPrivate.N.Bytes()), "e": encode(big.NewInt(int64(rsaPrivate.E)).Bytes()),
+			},
+			{
+				"kty": "EC", "kid": "ec", "crv": "P-256",
+				"x": encode(ecPrivate.X.FillBytes(make([]byte, 32))), "y": encode(ecPrivate.Y.FillBytes(make([]byte, 32))),
+			},
+			{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": encode(edPublic)},
+			{"kty": "RSA", "kid": "encryption", "use": "enc", "n": "AQAB", "e": "AQAB"},
+			{"kty": "oct", "kid": "secret", "k": "c2VjcmV0"},
+		}})
+	})
+	return testJWKS
+}
+
+// writeFile writes data to a file in a temporary directory and returns its path.
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+	return path
+}
+
+// newVerifier creates a JWTVerifier for the test keys.
+func newVerifier(t *testing.T, issuer, audience string) *auth.JWTVerifier {
+	t.Helper()
+	verifier, err := auth.NewJWTVerifier(writeFile(t, "jwks.json", testKeys(t)), issuer, audience)
+	if err != nil {
+		t.Fatalf("NewJWTVerifier() unexpected error: %v", err)
+	}
+	return verifier
+}
+
+// signToken creates a JWT with the claims signed by key.
+func signToken(key signingKey, claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": key.algorithm, "kid": key.id, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return input + "." + base64.RawURLEncoding.EncodeToString(key.sign([]byte(input)))
+}
+
+// claims returns valid claims for subject, with the overrides applied.
+func claims(subject string, overrides map[string]any) map[string]any {
+	c := map[string]any{
+		"sub": subject,
+		"iss": "https://auth.example.com",
+		"aud": "go-mcp-example",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for key, value := range overrides {
+		if value == nil {
+			delete(c, key)
+		} else {
+			c[key] = value
+		}
+	}
+	return c
+}
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	testKeys(t)
+	now := time.Now()
+	unsigned := func(token string) string {
+		return token[:strings.LastIndex(token, ".")+1]
+	}
+	tampered := func(token string) string {
+		parts := strings.Split(token, ".")
+		parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin","exp":9999999999}`))
+		return strings.Join(parts, ".")
+	}
+	noneToken := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin","exp":9999999999}`)) + "."
+
+	tests := []struct {
+		name    string
+		token   string
+		subject string
+		errMsg  string
+	}{
+		{name: "RS256", token: signToken(rsaKey, claims("alice", nil)), subject: "alice"},
+		{name: "ES256", token: signToken(ecKey, claims("bob", nil)), subject: "bob"},
+		{name: "EdDSA", token: signToken(edKey, claims("carol", nil)), subject: "carol"},
+		{
+			name:    "audience list",
+			token:   signToken(rsaKey, claims("alice", map[string]any{"aud": []string{"other", "go-mcp-example"}})),
+			subject: "alice",
+		},
+		{
+			name:    "expired within clock skew",
+			token:   signToken(rsaKey, claims("alice", map[string]any{"exp": now.Add(-30 * time.Second).Unix()})),
+			subject: "alice",
+		},
+		{
+			name:   "expired",
+			token:  signToken(rsaKey, claims("alice", map[string]any{"exp": now.Add(-time.Hour).Unix()})),
+			errMsg: "token has expired",
+		},
+		{
+			name:   "not valid yet",
+			token:  signToken(rsaKey, claims("alice", map[string]any{"nbf": now.Add(time.Hour).Unix()})),
+			errMsg: "token is not valid yet",
+		},
+		{
+			name:   "no expiry",
+			token:  signToken(rsaKey, claims("alice", map[string]any{"exp": nil})),
+			errMsg: "token has no expiry",
+		},
+		{
+			name:   "no subject",
+			token:  signToken(rsaKey, claims("", nil)),
+			errMsg: "token has no subject",
+		},
+		{
+			name:   "wrong issuer",
+			token:  signToken(rsaKey, claims("alice", map[string]any{"iss": "https://evil.example.com"})),
+			errMsg: "unexpected token issuer 'https://evil.example.com'",
+		},
+		{
+			name:   "wrong audience",
+			token:  signToken(rsaKey, claims("alice", map[string]any{"aud": "other"})),
+			errMsg: "token is not intended for this server",
+		},
+		{
+			name:   "unknown key",
+			token:  signToken(signingKey{id: "unknown", algorithm: "RS256", sign: rsaKey.sign}, claims("alice", nil)),
+			errMsg: "no RS256 key with ID 'unknown'",
+		},
+		{
+			name:   "algorithm not allowed for key",
+			token:  signToken(signingKey{id: "rsa", algorithm: "PS256", sign: rsaKey.sign}, claims("alice", nil)),
+			errMsg: "no PS256 key with ID 'rsa'",
+		},
+		{
+			name:   "tampered claims",
+			token:  tampered(signToken(rsaKey, claims("alice", nil))),
+			errMsg: "invalid token signature",
+		},
+		{name: "missing signature", token: unsigned(signToken(edKey, claims("carol", nil))), errMsg: "invalid token"},
+		{name: "alg none", token: noneToken, errMsg: "unsupported signing algorithm 'none'"},
+		{name: "malformed", token: "not-a-token", errMsg: "malformed token"},
+	}
+
+	verifier := newVerifier(t, "https://auth.example.com", "go-mcp-example")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject, err := verifier.Verify(tt.token)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Verify() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() unexpected error: %v", err)
+			}
+			if subject != tt.subject {
+				t.Errorf("Verify() subject = %q, want %q", subject, tt.subject)
+			}
+		})
+	}
+}
+
+func TestNewJWTVerifier_InvalidJWKS(t *testing.T) {
+	t.Parallel()
+
+	zeroCoordinate := base64.RawURLEncoding.EncodeToString(make([]byte, 32))
+	tests := []struct {
+		name   string
+		jwks   string
+		errMsg string
+	}{
+		{name: "not JSON", jwks: "keys", errMsg: "invalid JWKS file"},
+		{name: "no signing keys", jwks: `{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`, errMsg: "no signature verification keys"},
+		{name: "short RSA key", jwks: `{"keys":[{"kty":"RSA","kid":"short","n":"AQAB","e":"AQAB"}]}`, errMsg: "2048 bits"},
+		{
+			name:   "point not on curve",
+			jwks:   `{"keys":[{"kty":"EC","crv":"P-256","x":"` + zeroCoordinate + `","y":"` + zeroCoordinate + `"}]}`,
+			errMsg: "invalid JWKS file",
+		},
+		{name: "unsupported curve", jwks: `{"keys":[{"kty":"EC","crv":"P-192","x":"AQAB","y":"AQAB"}]}`, errMsg: "P-192"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := auth.NewJWTVerifier(writeFile(t, "jwks.json", []byte(tt.jwks)), "", "")
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("NewJWTVerifier() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+
+	if _, err := auth.NewJWTVerifier(filepath.Join(t.TempDir(), "missing.json"), "", ""); err == nil {
+		t.Error("NewJWTVerifier() with a missing file expected error but got none")
+	}
+}
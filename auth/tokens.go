@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// minTokenLength is the length of the shortest static token accepted, to rule out guessable tokens.
+const minTokenLength = 16
+
+// ParseTokens parses a comma-separated list of static tokens, each optionally prefixed with the name
+// of its caller and a colon, e.g. "ci:3f9a...,alice:77c2...". Caller names consist of letters, digits
+// and ".", "_", "-" or "@"; an entry whose part before the first colon is not a name is an unnamed
+// token, so tokens may contain colons. It returns the tokens mapped to their caller names; unnamed
+// tokens are named after a fingerprint of the token.
+func ParseTokens(list string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	for entry := range strings.SplitSeq(list, ",") {
+		if err := addToken(tokens, entry); err != nil {
+			return nil, err
+		}
+	}
+	return tokens, nil
+}
+
+// LoadTokens reads static tokens from a file with one token per line, in the format of ParseTokens.
+// Blank lines and lines starting with # are ignored.
+func LoadTokens(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tokens file: %w", err)
+	}
+	defer file.Close()
+
+	tokens := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.HasPrefix(strings.TrimSpace(scanner.Text()), "#") {
+			continue
+		}
+		if err = addToken(tokens, scanner.Text()); err != nil {
+			return nil, fmt.Errorf("invalid tokens file %s line %d: %w", path, line, err)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tokens file: %w", err)
+	}
+	return tokens, nil
+}
+
+// addToken parses a [name:]token entry into tokens, ignoring blank entries.
+func addToken(tokens map[string]string, entry string) error {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return nil
+	}
+
+	name, token, named := strings.Cut(entry, ":")
+	name, token = strings.TrimSpace(name), strings.TrimSpace(token)
+	if named && name == "" {
+		return errors.New("token has an empty caller name")
+	}
+	if !named || !isCallerName(name) {
+		token = entry
+		sum := sha256.Sum256([]byte(token))
+		name = "token-" + hex.EncodeToString(sum[:4])
+	}
+
+	if len(token) < minTokenLength {
+		return fmt.Errorf("token for caller '%s' is shorter than %d characters", name, minTokenLength)
+	}
+	if _, exists := tokens[token]; exists {
+		return fmt.Errorf("token for caller '%s' is listed twice", name)
+	}
+
+	tokens[token] = name
+	return nil
+}
+
+// isCallerName reports whether name can be the caller name of a token: letters, digits, ".", "_", "-"
+// and "@".
+func isCallerName(name string) bool {
+	return name != "" && strings.IndexFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("._-@", r)
+	}) < 0
+}
@@ -0,0 +1,83 @@
+package auth_test
+
+import (
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/rameshsunkara/go-mcp-example/auth"
+)
+
+func TestParseTokens(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		list   string
+		want   map[string]string
+		errMsg string
+	}{
+		{name: "empty", list: "", want: map[string]string{}},
+		{
+			name: "named tokens",
+			list: "ci:0123456789abcdef, alice : fedcba9876543210 ,",
+			want: map[string]string{"0123456789abcdef": "ci", "fedcba9876543210": "alice"},
+		},
+		{
+			name: "unnamed token",
+			list: "0123456789abcdef",
+			want: map[string]string{"0123456789abcdef": "token-9f9f5111"},
+		},
+		{
+			name: "token containing colons",
+			list: "ci:abc:0123456789abcdef, a1/b2+c3:d4e5f6g7h8i9",
+			want: map[string]string{"abc:0123456789abcdef": "ci", "a1/b2+c3:d4e5f6g7h8i9": "token-173cd296"},
+		},
+		{name: "short token", list: "ci:secret", errMsg: "token for caller 'ci' is shorter than 16 characters"},
+		{name: "empty name", list: ":0123456789abcdef", errMsg: "token has an empty caller name"},
+		{
+			name:   "duplicate token",
+			list:   "ci:0123456789abcdef,alice:0123456789abcdef",
+			errMsg: "token for caller 'alice' is listed twice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tokens, err := auth.ParseTokens(tt.list)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("ParseTokens() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTokens() unexpected error: %v", err)
+			}
+			if !maps.Equal(tokens, tt.want) {
+				t.Errorf("ParseTokens() = %v, want %v", tokens, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTokens(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, "tokens", []byte("# CI pipeline\nci:0123456789abcdef\n\n  # Alice\nalice:fedcba9876543210\n"))
+	tokens, err := auth.LoadTokens(path)
+	if err != nil {
+		t.Fatalf("LoadTokens() unexpected error: %v", err)
+	}
+	want := map[string]string{"0123456789abcdef": "ci", "fedcba9876543210": "alice"}
+	if !maps.Equal(tokens, want) {
+		t.Errorf("LoadTokens() = %v, want %v", tokens, want)
+	}
+
+	path = writeFile(t, "tokens", []byte("ci:0123456789abcdef\nalice:short\n"))
+	if _, err = auth.LoadTokens(path); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("LoadTokens() error = %v, want error on line 2", err)
+	}
+}
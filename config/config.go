@@ -5,12 +5,15 @@
 package config
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"net/url"
 	"os"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Config holds all configuration for the MCP server.
@@ -20,6 +23,56 @@ type Config struct {
 	LogFormat  string
 	APIKey     string // Secret - should only come from env vars for security, not flags
 	APIBaseURL string
+
+	// Response cache settings
+	CacheBackend       string // none, memory, file
+	CacheDir           string
+	CacheSize          int
+	CacheTTL           time.Duration
+	CacheRealtimeTTL   time.Duration
+	CacheHistoricalTTL time.Duration
+
+	// Upstream retry settings
+	RetryMaxAttempts int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+	RetryJitter      float64
+
+	// Client-side rate limiting for the API key quota
+	RateLimitRPS    float64
+	RateLimitBurst  int
+	RateLimitHourly int
+
+	// Realtime resource subscriptions
+	RealtimePollInterval time.Duration
+
+	// File-backed resources
+	ResourcesDir           string
+	ResourcesWatchInterval time.Duration
+
+	// Prompt templates
+	PromptsDir           string
+	PromptsWatchInterval time.Duration
+
+	// Time allowed for in-flight requests to finish on shutdown
+	ShutdownTimeout time.Duration
+
+	// How long an upstream ping result is reused by the readiness probe, 0 disables the ping
+	ReadinessPingTTL time.Duration
+
+	// Address of the Prometheus metrics listener, empty disables metrics
+	MetricsAddr string
+
+	// Tracing settings
+	TracingExporter string // none, otlp, stdout
+	OTLPEndpoint    string
+
+	// HTTP transport authentication, disabled when no tokens or JWKS file are set
+	AuthTokens      string // Secret - comma-separated [name:]token list, only from env vars
+	AuthTokensFile  string
+	AuthJWKSFile    string
+	AuthJWTIssuer   string
+	AuthJWTAudience string
 }
 
 // GetEnv returns the value of an environment variable or a default value.
@@ -30,6 +83,33 @@ func GetEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// GetEnvInt returns the integer value of an environment variable or a default value
+// if the variable is unset or not a valid integer.
+func GetEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetEnvDuration returns the duration value of an environment variable or a default value
+// if the variable is unset or not a valid duration.
+func GetEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetEnvFloat returns the float value of an environment variable or a default value
+// if the variable is unset or not a valid number.
+func GetEnvFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 // Load parses command-line flags (or custom args), validates the configuration, and returns it.
 // If no args are provided, it uses os.Args[1:] (command-line arguments)
 // If args are provided, it uses those instead (useful for testing).
@@ -45,6 +125,66 @@ func Load(args ...[]string) (*Config, error) {
 		"Log format: json, text (can also use LOG_FORMAT env var)")
 	apiBaseURL := fs.String("api-base-url", GetEnv("API_BASE_URL", "https://api.gsa.gov/analytics/dap/v2"),
 		"API base URL (can also use API_BASE_URL env var)")
+	cacheBackend := fs.String("cache", GetEnv("CACHE_BACKEND", "memory"),
+		"Response cache backend: none, memory, file (can also use CACHE_BACKEND env var)")
+	cacheDir := fs.String("cache-dir", GetEnv("CACHE_DIR", ""),
+		"Directory for the file cache backend (can also use CACHE_DIR env var)")
+	cacheSize := fs.Int("cache-size", GetEnvInt("CACHE_SIZE", 256),
+		"Maximum number of responses kept by the memory cache (can also use CACHE_SIZE env var)")
+	cacheTTL := fs.Duration("cache-ttl", GetEnvDuration("CACHE_TTL", 15*time.Minute),
+		"Cache TTL for reports that may still change (can also use CACHE_TTL env var)")
+	cacheRealtimeTTL := fs.Duration("cache-realtime-ttl", GetEnvDuration("CACHE_REALTIME_TTL", time.Minute),
+		"Cache TTL for the realtime report (can also use CACHE_REALTIME_TTL env var)")
+	cacheHistoricalTTL := fs.Duration("cache-historical-ttl", GetEnvDuration("CACHE_HISTORICAL_TTL", 24*time.Hour),
+		"Cache TTL for date ranges that ended before today (can also use CACHE_HISTORICAL_TTL env var)")
+	retryMaxAttempts := fs.Int("retry-max-attempts", GetEnvInt("RETRY_MAX_ATTEMPTS", 3),
+		"Maximum attempts per upstream request, 1 disables retries (can also use RETRY_MAX_ATTEMPTS env var)")
+	retryBaseDelay := fs.Duration("retry-base-delay", GetEnvDuration("RETRY_BASE_DELAY", 500*time.Millisecond),
+		"Delay before the first retry, doubled on each attempt (can also use RETRY_BASE_DELAY env var)")
+	retryMaxDelay := fs.Duration("retry-max-delay", GetEnvDuration("RETRY_MAX_DELAY", 10*time.Second),
+		"Maximum delay between retries, including Retry-After (can also use RETRY_MAX_DELAY env var)")
+	retryJitter := fs.Float64("retry-jitter", GetEnvFloat("RETRY_JITTER", 0.2),
+		"Random spread applied to retry delays, between 0 and 1 (can also use RETRY_JITTER env var)")
+	rateLimitRPS := fs.Float64("rate-limit-rps", GetEnvFloat("RATE_LIMIT_RPS", 2),
+		"Upstream requests per second, 0 disables the limit (can also use RATE_LIMIT_RPS env var)")
+	rateLimitBurst := fs.Int("rate-limit-burst", GetEnvInt("RATE_LIMIT_BURST", 5),
+		"Upstream requests allowed in a burst (can also use RATE_LIMIT_BURST env var)")
+	rateLimitHourly := fs.Int("rate-limit-hourly", GetEnvInt("RATE_LIMIT_HOURLY", 1000),
+		"Upstream requests allowed per hour, 0 disables the limit (can also use RATE_LIMIT_HOURLY env var)")
+	realtimePollInterval := fs.Duration("realtime-poll-interval", GetEnvDuration("REALTIME_POLL_INTERVAL", time.Minute),
+		"Realtime report polling interval for subscribers, 0 disables polling "+
+			"(can also use REALTIME_POLL_INTERVAL env var)")
+	resourcesDir := fs.String("resources-dir", GetEnv("RESOURCES_DIR", ""),
+		"Directory of Markdown, JSON and CSV files served as resources (can also use RESOURCES_DIR env var)")
+	resourcesWatchInterval := fs.Duration("resources-watch-interval",
+		GetEnvDuration("RESOURCES_WATCH_INTERVAL", 5*time.Second),
+		"Resources directory rescan interval, 0 disables watching (can also use RESOURCES_WATCH_INTERVAL env var)")
+	promptsDir := fs.String("prompts-dir", GetEnv("PROMPTS_DIR", ""),
+		"Directory of prompt templates overriding the built-in ones (can also use PROMPTS_DIR env var)")
+	promptsWatchInterval := fs.Duration("prompts-watch-interval", GetEnvDuration("PROMPTS_WATCH_INTERVAL", 5*time.Second),
+		"Prompt templates reload check interval, 0 disables reloading (can also use PROMPTS_WATCH_INTERVAL env var)")
+	shutdownTimeout := fs.Duration("shutdown-timeout", GetEnvDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
+		"Time allowed for in-flight requests to finish on shutdown before they are cancelled "+
+			"(can also use SHUTDOWN_TIMEOUT env var)")
+	readinessPingTTL := fs.Duration("readiness-ping-ttl", GetEnvDuration("READINESS_PING_TTL", 0),
+		"How long /readyz reuses an upstream API ping result, 0 disables the ping "+
+			"(can also use READINESS_PING_TTL env var)")
+	metricsAddr := fs.String("metrics-addr", GetEnv("METRICS_ADDR", ""),
+		"Address to serve Prometheus metrics on at /metrics, if empty metrics are disabled "+
+			"(can also use METRICS_ADDR env var)")
+	tracingExporter := fs.String("tracing", GetEnv("TRACING_EXPORTER", "none"),
+		"Trace exporter: none, otlp, stdout (can also use TRACING_EXPORTER env var)")
+	otlpEndpoint := fs.String("otlp-endpoint", GetEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318"),
+		"OTLP/HTTP collector endpoint for the otlp trace exporter (can also use OTEL_EXPORTER_OTLP_ENDPOINT env var)")
+	authTokensFile := fs.String("auth-tokens-file", GetEnv("AUTH_TOKENS_FILE", ""),
+		"File of [name:]token lines accepted as bearer tokens by the HTTP transport "+
+			"(can also use AUTH_TOKENS_FILE env var)")
+	authJWKSFile := fs.String("auth-jwks-file", GetEnv("AUTH_JWKS_FILE", ""),
+		"JWKS file of the keys that sign JWTs accepted by the HTTP transport (can also use AUTH_JWKS_FILE env var)")
+	authJWTIssuer := fs.String("auth-jwt-issuer", GetEnv("AUTH_JWT_ISSUER", ""),
+		"Required iss claim of accepted JWTs, if set (can also use AUTH_JWT_ISSUER env var)")
+	authJWTAudience := fs.String("auth-jwt-audience", GetEnv("AUTH_JWT_AUDIENCE", ""),
+		"Required aud claim of accepted JWTs, if set (can also use AUTH_JWT_AUDIENCE env var)")
 
 	// Determine which arguments to parse
 	var argsToUse []string
@@ -64,6 +204,44 @@ func Load(args ...[]string) (*Config, error) {
 		LogFormat:  *logFormat,
 		APIKey:     os.Getenv("API_KEY"),
 		APIBaseURL: *apiBaseURL,
+
+		CacheBackend:       *cacheBackend,
+		CacheDir:           *cacheDir,
+		CacheSize:          *cacheSize,
+		CacheTTL:           *cacheTTL,
+		CacheRealtimeTTL:   *cacheRealtimeTTL,
+		CacheHistoricalTTL: *cacheHistoricalTTL,
+
+		RetryMaxAttempts: *retryMaxAttempts,
+		RetryBaseDelay:   *retryBaseDelay,
+		RetryMaxDelay:    *retryMaxDelay,
+		RetryJitter:      *retryJitter,
+
+		RateLimitRPS:    *rateLimitRPS,
+		RateLimitBurst:  *rateLimitBurst,
+		RateLimitHourly: *rateLimitHourly,
+
+		RealtimePollInterval: *realtimePollInterval,
+
+		ResourcesDir:           *resourcesDir,
+		ResourcesWatchInterval: *resourcesWatchInterval,
+
+		PromptsDir:           *promptsDir,
+		PromptsWatchInterval: *promptsWatchInterval,
+
+		ShutdownTimeout:  *shutdownTimeout,
+		ReadinessPingTTL: *readinessPingTTL,
+
+		MetricsAddr: *metricsAddr,
+
+		TracingExporter: *tracingExporter,
+		OTLPEndpoint:    *otlpEndpoint,
+
+		AuthTokens:      os.Getenv("AUTH_TOKENS"),
+		AuthTokensFile:  *authTokensFile,
+		AuthJWKSFile:    *authJWKSFile,
+		AuthJWTIssuer:   *authJWTIssuer,
+		AuthJWTAudience: *authJWTAudience,
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -102,8 +280,178 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.MetricsAddr != "" && !strings.Contains(c.MetricsAddr, ":") {
+		return fmt.Errorf("invalid metrics address '%s', expected format 'host:port' or ':port'", c.MetricsAddr)
+	}
+
+	if err := c.validateTracing(); err != nil {
+		return err
+	}
+
+	if err := c.validateAuth(); err != nil {
+		return err
+	}
+
+	if err := c.validateCache(); err != nil {
+		return err
+	}
+
+	if err := c.validateRetry(); err != nil {
+		return err
+	}
+
+	if c.RateLimitRPS < 0 || c.RateLimitBurst < 0 || c.RateLimitHourly < 0 {
+		return errors.New("rate limits must not be negative")
+	}
+
+	if c.RealtimePollInterval < 0 {
+		return fmt.Errorf("invalid realtime poll interval %v, must not be negative", c.RealtimePollInterval)
+	}
+
+	if err := c.validateResources(); err != nil {
+		return err
+	}
+
+	if c.ShutdownTimeout < 0 {
+		return fmt.Errorf("invalid shutdown timeout %v, must not be negative", c.ShutdownTimeout)
+	}
+
+	if c.ReadinessPingTTL < 0 {
+		return fmt.Errorf("invalid readiness ping TTL %v, must not be negative", c.ReadinessPingTTL)
+	}
+
 	// APIKey validation could be added here if needed
 	// For example, checking minimum length, format, etc.
 
 	return nil
 }
+
+// validateCache checks the response cache settings. An empty backend disables caching.
+func (c *Config) validateCache() error {
+	validCacheBackends := []string{"none", "memory", "file"}
+	if c.CacheBackend != "" && !slices.Contains(validCacheBackends, strings.ToLower(c.CacheBackend)) {
+		return fmt.Errorf("invalid cache backend '%s', must be one of: %s",
+			c.CacheBackend, strings.Join(validCacheBackends, ", "))
+	}
+
+	if strings.EqualFold(c.CacheBackend, "file") && c.CacheDir == "" {
+		return errors.New("cache directory is required when using the file cache backend")
+	}
+
+	if c.CacheSize < 0 {
+		return fmt.Errorf("invalid cache size %d, must not be negative", c.CacheSize)
+	}
+
+	if c.CacheTTL < 0 || c.CacheRealtimeTTL < 0 || c.CacheHistoricalTTL < 0 {
+		return errors.New("cache TTLs must not be negative")
+	}
+
+	return nil
+}
+
+// validateResources checks the file-backed resource and prompt template settings. Empty directories
+// disable them.
+func (c *Config) validateResources() error {
+	if err := validateDir("resources", c.ResourcesDir); err != nil {
+		return err
+	}
+
+	if c.ResourcesWatchInterval < 0 {
+		return fmt.Errorf("invalid resources watch interval %v, must not be negative", c.ResourcesWatchInterval)
+	}
+
+	if err := validateDir("prompts", c.PromptsDir); err != nil {
+		return err
+	}
+
+	if c.PromptsWatchInterval < 0 {
+		return fmt.Errorf("invalid prompts watch interval %v, must not be negative", c.PromptsWatchInterval)
+	}
+
+	return nil
+}
+
+// validateDir checks that an optional directory setting names an existing directory.
+func validateDir(kind, dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("invalid %s directory '%s': %w", kind, dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("invalid %s directory '%s', not a directory", kind, dir)
+	}
+	return nil
+}
+
+// validateTracing checks the tracing settings. An empty exporter disables tracing.
+func (c *Config) validateTracing() error {
+	validExporters := []string{"none", "otlp", "stdout"}
+	if c.TracingExporter != "" && !slices.Contains(validExporters, strings.ToLower(c.TracingExporter)) {
+		return fmt.Errorf("invalid tracing exporter '%s', must be one of: %s",
+			c.TracingExporter, strings.Join(validExporters, ", "))
+	}
+
+	if strings.EqualFold(c.TracingExporter, "otlp") {
+		u, err := url.Parse(c.OTLPEndpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid OTLP endpoint '%s', expected an http or https URL", c.OTLPEndpoint)
+		}
+	}
+
+	return nil
+}
+
+// validateAuth checks the HTTP transport authentication settings. The tokens and keys themselves are
+// parsed when the server starts.
+func (c *Config) validateAuth() error {
+	if err := validateFile("tokens", c.AuthTokensFile); err != nil {
+		return err
+	}
+
+	if err := validateFile("JWKS", c.AuthJWKSFile); err != nil {
+		return err
+	}
+
+	if c.AuthJWKSFile == "" && (c.AuthJWTIssuer != "" || c.AuthJWTAudience != "") {
+		return errors.New("JWT issuer and audience require a JWKS file")
+	}
+
+	return nil
+}
+
+// validateFile checks that an optional file setting names an existing regular file.
+func validateFile(kind, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("invalid %s file '%s': %w", kind, path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("invalid %s file '%s', not a regular file", kind, path)
+	}
+	return nil
+}
+
+// validateRetry checks the upstream retry settings. Zero values disable retries.
+func (c *Config) validateRetry() error {
+	if c.RetryMaxAttempts < 0 {
+		return fmt.Errorf("invalid retry max attempts %d, must not be negative", c.RetryMaxAttempts)
+	}
+
+	if c.RetryBaseDelay < 0 || c.RetryMaxDelay < 0 {
+		return errors.New("retry delays must not be negative")
+	}
+
+	if c.RetryJitter < 0 || c.RetryJitter > 1 {
+		return fmt.Errorf("invalid retry jitter %v, must be between 0 and 1", c.RetryJitter)
+	}
+
+	return nil
+}
@@ -1,8 +1,11 @@
 package config_test
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/rameshsunkara/go-mcp-example/config"
 )
@@ -151,6 +154,199 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid file cache config",
+			config: config.Config{
+				LogLevel:     "info",
+				LogFormat:    "json",
+				CacheBackend: "file",
+				CacheDir:     "/tmp/cache",
+				CacheSize:    10,
+				CacheTTL:     time.Minute,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid cache backend",
+			config: config.Config{
+				LogLevel:     "info",
+				LogFormat:    "json",
+				CacheBackend: "redis",
+			},
+			wantErr: true,
+			errMsg:  "invalid cache backend 'redis'",
+		},
+		{
+			name: "file cache without directory",
+			config: config.Config{
+				LogLevel:     "info",
+				LogFormat:    "json",
+				CacheBackend: "file",
+			},
+			wantErr: true,
+			errMsg:  "cache directory is required",
+		},
+		{
+			name: "negative cache TTL",
+			config: config.Config{
+				LogLevel:  "info",
+				LogFormat: "json",
+				CacheTTL:  -time.Second,
+			},
+			wantErr: true,
+			errMsg:  "cache TTLs must not be negative",
+		},
+		{
+			name: "negative retry attempts",
+			config: config.Config{
+				LogLevel:         "info",
+				LogFormat:        "json",
+				RetryMaxAttempts: -1,
+			},
+			wantErr: true,
+			errMsg:  "invalid retry max attempts -1",
+		},
+		{
+			name: "retry jitter out of range",
+			config: config.Config{
+				LogLevel:    "info",
+				LogFormat:   "json",
+				RetryJitter: 1.5,
+			},
+			wantErr: true,
+			errMsg:  "invalid retry jitter 1.5",
+		},
+		{
+			name: "negative rate limit",
+			config: config.Config{
+				LogLevel:     "info",
+				LogFormat:    "json",
+				RateLimitRPS: -1,
+			},
+			wantErr: true,
+			errMsg:  "rate limits must not be negative",
+		},
+		{
+			name: "negative realtime poll interval",
+			config: config.Config{
+				LogLevel:             "info",
+				LogFormat:            "json",
+				RealtimePollInterval: -time.Second,
+			},
+			wantErr: true,
+			errMsg:  "invalid realtime poll interval -1s",
+		},
+		{
+			name: "missing resources directory",
+			config: config.Config{
+				LogLevel:     "info",
+				LogFormat:    "json",
+				ResourcesDir: "/nonexistent/go-mcp-resources",
+			},
+			wantErr: true,
+			errMsg:  "invalid resources directory '/nonexistent/go-mcp-resources'",
+		},
+		{
+			name: "negative resources watch interval",
+			config: config.Config{
+				LogLevel:               "info",
+				LogFormat:              "json",
+				ResourcesWatchInterval: -time.Second,
+			},
+			wantErr: true,
+			errMsg:  "invalid resources watch interval -1s",
+		},
+		{
+			name: "missing prompts directory",
+			config: config.Config{
+				LogLevel:   "info",
+				LogFormat:  "json",
+				PromptsDir: "/nonexistent/go-mcp-prompts",
+			},
+			wantErr: true,
+			errMsg:  "invalid prompts directory '/nonexistent/go-mcp-prompts'",
+		},
+		{
+			name: "negative shutdown timeout",
+			config: config.Config{
+				LogLevel:        "info",
+				LogFormat:       "json",
+				ShutdownTimeout: -time.Second,
+			},
+			wantErr: true,
+			errMsg:  "invalid shutdown timeout -1s",
+		},
+		{
+			name: "negative readiness ping TTL",
+			config: config.Config{
+				LogLevel:         "info",
+				LogFormat:        "json",
+				ReadinessPingTTL: -time.Minute,
+			},
+			wantErr: true,
+			errMsg:  "invalid readiness ping TTL -1m0s",
+		},
+		{
+			name: "invalid metrics address",
+			config: config.Config{
+				LogLevel:    "info",
+				LogFormat:   "json",
+				MetricsAddr: "9090",
+			},
+			wantErr: true,
+			errMsg:  "invalid metrics address '9090'",
+		},
+		{
+			name: "invalid tracing exporter",
+			config: config.Config{
+				LogLevel:        "info",
+				LogFormat:       "json",
+				TracingExporter: "jaeger",
+			},
+			wantErr: true,
+			errMsg:  "invalid tracing exporter 'jaeger'",
+		},
+		{
+			name: "invalid OTLP endpoint",
+			config: config.Config{
+				LogLevel:        "info",
+				LogFormat:       "json",
+				TracingExporter: "otlp",
+				OTLPEndpoint:    "localhost:4318",
+			},
+			wantErr: true,
+			errMsg:  "invalid OTLP endpoint 'localhost:4318'",
+		},
+		{
+			name: "OTLP endpoint is only checked for the otlp exporter",
+			config: config.Config{
+				LogLevel:        "info",
+				LogFormat:       "json",
+				TracingExporter: "stdout",
+				OTLPEndpoint:    "localhost:4318",
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing JWKS file",
+			config: config.Config{
+				LogLevel:     "info",
+				LogFormat:    "json",
+				AuthJWKSFile: "/nonexistent/jwks.json",
+			},
+			wantErr: true,
+			errMsg:  "invalid JWKS file '/nonexistent/jwks.json'",
+		},
+		{
+			name: "JWT issuer without JWKS file",
+			config: config.Config{
+				LogLevel:      "info",
+				LogFormat:     "json",
+				AuthJWTIssuer: "https://auth.example.com",
+			},
+			wantErr: true,
+			errMsg:  "JWT issuer and audience require a JWKS file",
+		},
 		{
 			name: "empty API base URL is valid",
 			config: config.Config{
@@ -239,6 +435,222 @@ func TestLoadWithEmptyArgs(t *testing.T) {
 	if cfg.APIBaseURL != "https://api.gsa.gov/analytics/dap/v2" {
 		t.Errorf("Load() APIBaseURL = %v, want https://api.gsa.gov/analytics/dap/v2", cfg.APIBaseURL)
 	}
+	if cfg.CacheBackend != "memory" {
+		t.Errorf("Load() CacheBackend = %v, want memory", cfg.CacheBackend)
+	}
+	if cfg.CacheRealtimeTTL >= cfg.CacheHistoricalTTL {
+		t.Errorf("Load() CacheRealtimeTTL = %v should be shorter than CacheHistoricalTTL = %v",
+			cfg.CacheRealtimeTTL, cfg.CacheHistoricalTTL)
+	}
+}
+
+func TestLoadRetrySettings(t *testing.T) {
+	t.Setenv("RETRY_MAX_ATTEMPTS", "5")
+	t.Setenv("RETRY_JITTER", "0.5")
+
+	cfg, err := config.Load([]string{"--retry-base-delay", "250ms"})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	if cfg.RetryMaxAttempts != 5 {
+		t.Errorf("Load() RetryMaxAttempts = %d, want 5", cfg.RetryMaxAttempts)
+	}
+	if cfg.RetryBaseDelay != 250*time.Millisecond {
+		t.Errorf("Load() RetryBaseDelay = %v, want 250ms", cfg.RetryBaseDelay)
+	}
+	if cfg.RetryMaxDelay != 10*time.Second {
+		t.Errorf("Load() RetryMaxDelay = %v, want default 10s", cfg.RetryMaxDelay)
+	}
+	if cfg.RetryJitter != 0.5 {
+		t.Errorf("Load() RetryJitter = %v, want 0.5", cfg.RetryJitter)
+	}
+}
+
+func TestLoadRateLimitSettings(t *testing.T) {
+	t.Setenv("RATE_LIMIT_RPS", "0.5")
+	t.Setenv("RATE_LIMIT_HOURLY", "500")
+
+	cfg, err := config.Load([]string{"--rate-limit-burst", "8"})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	if cfg.RateLimitRPS != 0.5 {
+		t.Errorf("Load() RateLimitRPS = %v, want 0.5", cfg.RateLimitRPS)
+	}
+	if cfg.RateLimitBurst != 8 {
+		t.Errorf("Load() RateLimitBurst = %d, want 8", cfg.RateLimitBurst)
+	}
+	if cfg.RateLimitHourly != 500 {
+		t.Errorf("Load() RateLimitHourly = %d, want 500", cfg.RateLimitHourly)
+	}
+}
+
+func TestLoadCacheSettings(t *testing.T) {
+	t.Setenv("CACHE_BACKEND", "file")
+	t.Setenv("CACHE_DIR", "/tmp/dap-cache")
+	t.Setenv("CACHE_SIZE", "not-a-number")
+	t.Setenv("CACHE_TTL", "5m")
+
+	cfg, err := config.Load([]string{"--cache-realtime-ttl", "30s"})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	if cfg.CacheBackend != "file" || cfg.CacheDir != "/tmp/dap-cache" {
+		t.Errorf("Load() cache backend = %v (%v), want file (/tmp/dap-cache)", cfg.CacheBackend, cfg.CacheDir)
+	}
+	if cfg.CacheSize != 256 {
+		t.Errorf("Load() CacheSize = %d, want default 256 for invalid env value", cfg.CacheSize)
+	}
+	if cfg.CacheTTL != 5*time.Minute {
+		t.Errorf("Load() CacheTTL = %v, want 5m", cfg.CacheTTL)
+	}
+	if cfg.CacheRealtimeTTL != 30*time.Second {
+		t.Errorf("Load() CacheRealtimeTTL = %v, want 30s", cfg.CacheRealtimeTTL)
+	}
+}
+
+func TestLoadRealtimePollInterval(t *testing.T) {
+	t.Setenv("REALTIME_POLL_INTERVAL", "")
+
+	cfg, err := config.Load([]string{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.RealtimePollInterval != time.Minute {
+		t.Errorf("Load() RealtimePollInterval = %v, want default 1m", cfg.RealtimePollInterval)
+	}
+
+	t.Setenv("REALTIME_POLL_INTERVAL", "15s")
+	cfg, err = config.Load([]string{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.RealtimePollInterval != 15*time.Second {
+		t.Errorf("Load() RealtimePollInterval = %v, want 15s", cfg.RealtimePollInterval)
+	}
+}
+
+func TestLoadResourcesSettings(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("RESOURCES_DIR", dir)
+	t.Setenv("RESOURCES_WATCH_INTERVAL", "")
+
+	cfg, err := config.Load([]string{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.ResourcesDir != dir {
+		t.Errorf("Load() ResourcesDir = %v, want %v", cfg.ResourcesDir, dir)
+	}
+	if cfg.ResourcesWatchInterval != 5*time.Second {
+		t.Errorf("Load() ResourcesWatchInterval = %v, want default 5s", cfg.ResourcesWatchInterval)
+	}
+
+	file := filepath.Join(dir, "notes.md")
+	if err = os.WriteFile(file, []byte("# Notes"), 0o600); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+	if _, err = config.Load([]string{"--resources-dir", file}); err == nil {
+		t.Error("Load() with a file as resources directory expected error but got none")
+	}
+
+	cfg, err = config.Load([]string{"--prompts-dir", dir, "--prompts-watch-interval", "0"})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.PromptsDir != dir || cfg.PromptsWatchInterval != 0 {
+		t.Errorf("Load() prompts = %v (%v), want %v (0s)", cfg.PromptsDir, cfg.PromptsWatchInterval, dir)
+	}
+}
+
+func TestLoadShutdownTimeout(t *testing.T) {
+	t.Setenv("SHUTDOWN_TIMEOUT", "")
+
+	cfg, err := config.Load([]string{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.ShutdownTimeout != 10*time.Second {
+		t.Errorf("Load() ShutdownTimeout = %v, want default 10s", cfg.ShutdownTimeout)
+	}
+
+	t.Setenv("SHUTDOWN_TIMEOUT", "30s")
+	cfg, err = config.Load([]string{"--shutdown-timeout", "2s"})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.ShutdownTimeout != 2*time.Second {
+		t.Errorf("Load() ShutdownTimeout = %v, want the flag value 2s", cfg.ShutdownTimeout)
+	}
+}
+
+func TestLoadReadinessPingTTL(t *testing.T) {
+	t.Setenv("READINESS_PING_TTL", "")
+
+	cfg, err := config.Load([]string{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.ReadinessPingTTL != 0 {
+		t.Errorf("Load() ReadinessPingTTL = %v, want default 0s", cfg.ReadinessPingTTL)
+	}
+
+	t.Setenv("READINESS_PING_TTL", "5m")
+	cfg, err = config.Load([]string{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.ReadinessPingTTL != 5*time.Minute {
+		t.Errorf("Load() ReadinessPingTTL = %v, want 5m", cfg.ReadinessPingTTL)
+	}
+}
+
+func TestLoadMetricsAddr(t *testing.T) {
+	t.Setenv("METRICS_ADDR", "")
+
+	cfg, err := config.Load([]string{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.MetricsAddr != "" {
+		t.Errorf("Load() MetricsAddr = %q, want metrics disabled by default", cfg.MetricsAddr)
+	}
+
+	t.Setenv("METRICS_ADDR", ":9090")
+	cfg, err = config.Load([]string{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.MetricsAddr != ":9090" {
+		t.Errorf("Load() MetricsAddr = %q, want :9090", cfg.MetricsAddr)
+	}
+}
+
+func TestLoadTracingSettings(t *testing.T) {
+	t.Setenv("TRACING_EXPORTER", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+	cfg, err := config.Load([]string{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.TracingExporter != "none" || cfg.OTLPEndpoint != "http://localhost:4318" {
+		t.Errorf("Load() tracing = %q (%q), want none (http://localhost:4318)", cfg.TracingExporter, cfg.OTLPEndpoint)
+	}
+
+	t.Setenv("TRACING_EXPORTER", "otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "https://collector.example.com:4318")
+	cfg, err = config.Load([]string{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.TracingExporter != "otlp" || cfg.OTLPEndpoint != "https://collector.example.com:4318" {
+		t.Errorf("Load() tracing = %q (%q), want otlp (https://collector.example.com:4318)",
+			cfg.TracingExporter, cfg.OTLPEndpoint)
+	}
 }
 
 // Helper function to check if a string contains a substring.
@@ -388,3 +800,40 @@ func getLoadTestCases() []struct {
 		},
 	}
 }
+
+func TestLoadAuthSettings(t *testing.T) {
+	dir := t.TempDir()
+	tokensFile := filepath.Join(dir, "tokens")
+	if err := os.WriteFile(tokensFile, []byte("ci:0123456789abcdef\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+	t.Setenv("AUTH_TOKENS", "alice:fedcba9876543210")
+	t.Setenv("AUTH_TOKENS_FILE", tokensFile)
+	t.Setenv("AUTH_JWKS_FILE", "")
+	t.Setenv("AUTH_JWT_ISSUER", "")
+	t.Setenv("AUTH_JWT_AUDIENCE", "")
+
+	cfg, err := config.Load([]string{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.AuthTokens != "alice:fedcba9876543210" || cfg.AuthTokensFile != tokensFile {
+		t.Errorf("Load() tokens = %q (%q), want alice:fedcba9876543210 (%q)",
+			cfg.AuthTokens, cfg.AuthTokensFile, tokensFile)
+	}
+
+	if _, err = config.Load([]string{"--auth-tokens-file", dir}); err == nil {
+		t.Error("Load() with a directory as tokens file expected error but got none")
+	}
+
+	cfg, err = config.Load([]string{"--auth-jwks-file", tokensFile, "--auth-jwt-issuer", "https://auth.example.com",
+		"--auth-jwt-audience", "go-mcp-example"})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.AuthJWKSFile != tokensFile || cfg.AuthJWTIssuer != "https://auth.example.com" ||
+		cfg.AuthJWTAudience != "go-mcp-example" {
+		t.Errorf("Load() JWT = %q (%q, %q), want %q (https://auth.example.com, go-mcp-example)",
+			cfg.AuthJWKSFile, cfg.AuthJWTIssuer, cfg.AuthJWTAudience, tokensFile)
+	}
+}
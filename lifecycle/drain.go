@@ -0,0 +1,88 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ErrShuttingDown is returned for requests received after draining started.
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// Drainer tracks the in-flight MCP requests of a server so that shutdown can wait for them to
+// finish, and cancels the ones still running when the drain timeout expires.
+//
+// The MCP SDK does not cancel the context of a request when its connection is closed, so without
+// the Drainer outstanding API fetches would keep running after shutdown.
+type Drainer struct {
+	abort  context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	draining bool
+	inFlight sync.WaitGroup
+}
+
+// NewDrainer creates a Drainer. Add its Middleware to the server to track requests.
+func NewDrainer() *Drainer {
+	abort, cancel := context.WithCancel(context.Background())
+	return &Drainer{abort: abort, cancel: cancel}
+}
+
+// Middleware tracks requests until they return and cancels their context when Drain gives up
+// waiting. Requests received while draining are rejected; notifications are passed through.
+func (d *Drainer) Middleware(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
+	return func(ctx context.Context, ss *mcp.ServerSession, method string, params mcp.Params) (mcp.Result, error) {
+		if strings.HasPrefix(method, "notifications/") {
+			return next(ctx, ss, method, params)
+		}
+
+		d.mu.Lock()
+		if d.draining {
+			d.mu.Unlock()
+			return nil, ErrShuttingDown
+		}
+		d.inFlight.Add(1)
+		d.mu.Unlock()
+		defer d.inFlight.Done()
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stop := context.AfterFunc(d.abort, cancel)
+		defer stop()
+
+		return next(ctx, ss, method, params)
+	}
+}
+
+// Draining reports whether Drain has been called.
+func (d *Drainer) Draining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+// Drain stops accepting requests and waits for the in-flight ones to return. If ctx is done first,
+// the remaining requests are cancelled and the context error is returned.
+func (d *Drainer) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		return ctx.Err()
+	}
+}
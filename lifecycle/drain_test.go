@@ -0,0 +1,105 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/lifecycle"
+)
+
+// blockingHandler returns a handler that signals started when called and returns once release is
+// closed or its context is cancelled.
+func blockingHandler(started chan<- struct{}, release <-chan struct{}) mcp.MethodHandler[*mcp.ServerSession] {
+	return func(ctx context.Context, _ *mcp.ServerSession, _ string, _ mcp.Params) (mcp.Result, error) {
+		started <- struct{}{}
+		select {
+		case <-release:
+			return &mcp.CallToolResult{}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func TestDrainer_Drain(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		release  bool
+		timeout  time.Duration
+		wantErr  error
+		wantCall error
+	}{
+		{
+			name:    "in-flight request finishes",
+			release: true,
+			timeout: 5 * time.Second,
+		},
+		{
+			name:     "in-flight request is cancelled after the timeout",
+			timeout:  10 * time.Millisecond,
+			wantErr:  context.DeadlineExceeded,
+			wantCall: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			drainer := lifecycle.NewDrainer()
+			started := make(chan struct{}, 1)
+			release := make(chan struct{})
+			handler := drainer.Middleware(blockingHandler(started, release))
+
+			callErr := make(chan error, 1)
+			go func() {
+				_, err := handler(context.Background(), nil, "tools/call", nil)
+				callErr <- err
+			}()
+			<-started
+
+			if tt.release {
+				close(release)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			if err := drainer.Drain(ctx); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Drain() error = %v, want %v", err, tt.wantErr)
+			}
+			if err := <-callErr; !errors.Is(err, tt.wantCall) {
+				t.Errorf("In-flight request error = %v, want %v", err, tt.wantCall)
+			}
+		})
+	}
+}
+
+func TestDrainer_Middleware_RejectsWhileDraining(t *testing.T) {
+	t.Parallel()
+
+	drainer := lifecycle.NewDrainer()
+	if err := drainer.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain() without requests unexpected error: %v", err)
+	}
+
+	var calls []string
+	handler := drainer.Middleware(func(_ context.Context, _ *mcp.ServerSession, method string,
+		_ mcp.Params) (mcp.Result, error) {
+		calls = append(calls, method)
+		return nil, nil
+	})
+
+	if _, err := handler(context.Background(), nil, "tools/call", nil); !errors.Is(err, lifecycle.ErrShuttingDown) {
+		t.Errorf("Request while draining error = %v, want %v", err, lifecycle.ErrShuttingDown)
+	}
+	if _, err := handler(context.Background(), nil, "notifications/cancelled", nil); err != nil {
+		t.Errorf("Notification while draining unexpected error: %v", err)
+	}
+	if len(calls) != 1 || calls[0] != "notifications/cancelled" {
+		t.Errorf("Handled methods = %v, want only the notification", calls)
+	}
+}
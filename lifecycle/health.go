@@ -0,0 +1,173 @@
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/config"
+)
+
+// pingTimeout bounds the upstream ping made by the readiness probe.
+const pingTimeout = 5 * time.Second
+
+// Readiness check results. Failures are logged rather than returned, as the probe endpoints are
+// unauthenticated.
+const (
+	checkOK       = "ok"
+	checkSkipped  = "skipped"
+	checkFailed   = "failed"
+	checkDraining = "draining"
+)
+
+// BuildInfo describes the running binary.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	Module    string `json:"module,omitempty"`
+	Revision  string `json:"revision,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+}
+
+// ReadBuildInfo returns the build information embedded in the binary, labeled with version, the
+// version set at link time.
+func ReadBuildInfo(version string) BuildInfo {
+	info := BuildInfo{Version: version}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info.GoVersion = bi.GoVersion
+	info.Module = bi.Main.Path
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.BuildTime = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+// readiness is the body of a /readyz response.
+type readiness struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Health serves the health, readiness and version endpoints of the HTTP transport.
+type Health struct {
+	logger  *slog.Logger
+	cfg     *config.Config
+	build   BuildInfo
+	ping    func(context.Context) error
+	pingTTL time.Duration
+	drainer *Drainer
+
+	mu       sync.Mutex
+	pingErr  error
+	pingedAt time.Time
+}
+
+// NewHealth creates a Health for the given configuration and build. When ping is set and the
+// configured readiness ping TTL is positive, readiness also requires a successful ping, whose
+// result is reused for the TTL. When drainer is set, the server is not ready once it drains.
+func NewHealth(logger *slog.Logger, cfg *config.Config, build BuildInfo, ping func(context.Context) error,
+	drainer *Drainer) *Health {
+	return &Health{
+		logger:  logger,
+		cfg:     cfg,
+		build:   build,
+		ping:    ping,
+		pingTTL: cfg.ReadinessPingTTL,
+		drainer: drainer,
+	}
+}
+
+// Register adds the /healthz, /readyz and /version endpoints to mux.
+func (h *Health) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /healthz", h.HandleHealthz)
+	mux.HandleFunc("GET /readyz", h.HandleReadyz)
+	mux.HandleFunc("GET /version", h.HandleVersion)
+}
+
+// HandleHealthz reports that the process is alive.
+func (h *Health) HandleHealthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": checkOK})
+}
+
+// HandleReadyz reports whether the server can serve requests: it must not be shutting down, the
+// configuration must be valid and, if enabled, the upstream API must answer a ping.
+func (h *Health) HandleReadyz(w http.ResponseWriter, r *http.Request) {
+	result := readiness{
+		Status: checkOK,
+		Checks: map[string]string{"shutdown": checkOK, "config": checkOK, "upstream": checkSkipped},
+	}
+
+	if h.drainer != nil && h.drainer.Draining() {
+		result.Checks["shutdown"] = checkDraining
+		result.Status = "unavailable"
+	}
+
+	if err := h.cfg.Validate(); err != nil {
+		h.logger.WarnContext(r.Context(), "Readiness check failed", "check", "config", "error", err)
+		result.Checks["config"] = checkFailed
+		result.Status = "unavailable"
+	}
+
+	if h.ping != nil && h.pingTTL > 0 {
+		result.Checks["upstream"] = checkOK
+		if err := h.checkUpstream(r.Context()); err != nil {
+			h.logger.WarnContext(r.Context(), "Readiness check failed", "check", "upstream", "error", err)
+			result.Checks["upstream"] = checkFailed
+			result.Status = "unavailable"
+		}
+	}
+
+	status := http.StatusOK
+	if result.Status != checkOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, result)
+}
+
+// HandleVersion reports the build information.
+func (h *Health) HandleVersion(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.build)
+}
+
+// checkUpstream pings the upstream API, reusing the previous result for the ping TTL so that
+// frequent probes do not use up the API quota. Concurrent probes share one ping.
+func (h *Health) checkUpstream(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	if !h.pingedAt.IsZero() && now.Sub(h.pingedAt) < h.pingTTL {
+		return h.pingErr
+	}
+
+	// A probe that hangs up must not leave a cancellation error cached for the next ones
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), pingTimeout)
+	defer cancel()
+	h.pingErr = h.ping(ctx)
+	h.pingedAt = now
+	return h.pingErr
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
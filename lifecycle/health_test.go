@@ -0,0 +1,160 @@
+package lifecycle_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/config"
+	"github.com/rameshsunkara/go-mcp-example/lifecycle"
+)
+
+// discard is a logger dropping the failed readiness checks.
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// get serves a GET request for path and decodes the JSON response body.
+func get(t *testing.T, mux *http.ServeMux, path string) (int, map[string]any) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var body map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("GET %s returned invalid JSON: %v", path, err)
+	}
+	return rec.Code, body
+}
+
+func TestHealth_Healthz(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	lifecycle.NewHealth(discard, &config.Config{LogLevel: "info", LogFormat: "json"}, lifecycle.BuildInfo{}, nil, nil).
+		Register(mux)
+
+	status, body := get(t, mux, "/healthz")
+	if status != http.StatusOK || body["status"] != "ok" {
+		t.Errorf("GET /healthz = %d %v, want 200 ok", status, body)
+	}
+}
+
+func TestHealth_Readyz(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		cfg            config.Config
+		ping           func(context.Context) error
+		draining       bool
+		expectedStatus int
+		expectedChecks map[string]string
+	}{
+		{
+			name:           "ready without ping",
+			cfg:            config.Config{LogLevel: "info", LogFormat: "json"},
+			ping:           func(context.Context) error { return errors.New("not called") },
+			expectedStatus: http.StatusOK,
+			expectedChecks: map[string]string{"shutdown": "ok", "config": "ok", "upstream": "skipped"},
+		},
+		{
+			name:           "ready with ping",
+			cfg:            config.Config{LogLevel: "info", LogFormat: "json", ReadinessPingTTL: time.Minute},
+			ping:           func(context.Context) error { return nil },
+			expectedStatus: http.StatusOK,
+			expectedChecks: map[string]string{"config": "ok", "upstream": "ok"},
+		},
+		{
+			name:           "upstream unavailable",
+			cfg:            config.Config{LogLevel: "info", LogFormat: "json", ReadinessPingTTL: time.Minute},
+			ping:           func(context.Context) error { return errors.New("ping failed with status 403") },
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"config": "ok", "upstream": "failed"},
+		},
+		{
+			name:           "invalid config",
+			cfg:            config.Config{LogLevel: "verbose", LogFormat: "json"},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"config": "failed", "upstream": "skipped"},
+		},
+		{
+			name:           "draining",
+			cfg:            config.Config{LogLevel: "info", LogFormat: "json", ReadinessPingTTL: time.Minute},
+			ping:           func(context.Context) error { return nil },
+			draining:       true,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"shutdown": "draining", "config": "ok", "upstream": "ok"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			drainer := lifecycle.NewDrainer()
+			if tt.draining {
+				if err := drainer.Drain(context.Background()); err != nil {
+					t.Fatalf("Drain() unexpected error: %v", err)
+				}
+			}
+			mux := http.NewServeMux()
+			lifecycle.NewHealth(discard, &tt.cfg, lifecycle.BuildInfo{}, tt.ping, drainer).Register(mux)
+
+			status, body := get(t, mux, "/readyz")
+			if status != tt.expectedStatus {
+				t.Errorf("GET /readyz status = %d, want %d", status, tt.expectedStatus)
+			}
+			checks, _ := body["checks"].(map[string]any)
+			for name, want := range tt.expectedChecks {
+				if checks[name] != want {
+					t.Errorf("GET /readyz check %s = %v, want %q", name, checks[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestHealth_Readyz_CachesPing(t *testing.T) {
+	t.Parallel()
+
+	var pings atomic.Int32
+	cfg := &config.Config{LogLevel: "info", LogFormat: "json", ReadinessPingTTL: time.Hour}
+	mux := http.NewServeMux()
+	lifecycle.NewHealth(discard, cfg, lifecycle.BuildInfo{}, func(context.Context) error {
+		pings.Add(1)
+		return nil
+	}, nil).Register(mux)
+
+	for range 3 {
+		if status, _ := get(t, mux, "/readyz"); status != http.StatusOK {
+			t.Fatalf("GET /readyz status = %d, want 200", status)
+		}
+	}
+	if got := pings.Load(); got != 1 {
+		t.Errorf("Upstream pinged %d times, want 1", got)
+	}
+}
+
+func TestHealth_Version(t *testing.T) {
+	t.Parallel()
+
+	build := lifecycle.ReadBuildInfo("1a2b3c4")
+	if build.Version != "1a2b3c4" || build.GoVersion == "" {
+		t.Errorf("ReadBuildInfo() = %+v, want version 1a2b3c4 and the Go version", build)
+	}
+
+	mux := http.NewServeMux()
+	lifecycle.NewHealth(discard, &config.Config{}, build, nil, nil).Register(mux)
+
+	status, body := get(t, mux, "/version")
+	if status != http.StatusOK || body["version"] != "1a2b3c4" || body["go_version"] != build.GoVersion {
+		t.Errorf("GET /version = %d %v, want 200 with the build info", status, body)
+	}
+}
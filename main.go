@@ -2,23 +2,42 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/auth"
 	"github.com/rameshsunkara/go-mcp-example/config"
+	"github.com/rameshsunkara/go-mcp-example/lifecycle"
 	"github.com/rameshsunkara/go-mcp-example/log"
+	"github.com/rameshsunkara/go-mcp-example/metrics"
+	"github.com/rameshsunkara/go-mcp-example/models"
 	"github.com/rameshsunkara/go-mcp-example/prompts"
 	"github.com/rameshsunkara/go-mcp-example/resources"
 	"github.com/rameshsunkara/go-mcp-example/tools"
+	"github.com/rameshsunkara/go-mcp-example/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
+// version is the build version, set at link time by the Makefile.
+var version = "dev"
+
 const (
 	readTimeoutSeconds  = 30
 	writeTimeoutSeconds = 30
 	idleTimeoutSeconds  = 60
+
+	// tracerShutdownTimeout bounds the export of the remaining spans on exit
+	tracerShutdownTimeout = 5 * time.Second
 )
 
 func main() {
@@ -29,82 +48,381 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create logger with specified level and format
+	// Create logger with specified level and format, logging the authenticated caller of each request
 	useTextFormat := cfg.LogFormat == "text"
-	logger := log.New(cfg.LogLevel, useTextFormat)
+	logger := slog.New(auth.NewLogHandler(log.New(cfg.LogLevel, useTextFormat).Handler()))
+
+	// Cancel the root context on SIGINT or SIGTERM to shut down gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err = run(ctx, cfg, logger)
+	stop()
+	if err != nil {
+		logger.Error("Server failed", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("MCP server stopped")
+}
 
+// run serves MCP until ctx is cancelled or the transport fails. On cancellation, in-flight requests
+// are given cfg.ShutdownTimeout to finish before they are cancelled.
+func run(ctx context.Context, cfg *config.Config, logger *slog.Logger) error {
 	logger.Info("Starting MCP server",
 		"name", "go-mcp-example",
+		"version", version,
 		"log_level", cfg.LogLevel,
-		"log_format", cfg.LogFormat)
+		"log_format", cfg.LogFormat,
+		"cache_backend", cfg.CacheBackend)
 
-	server := mcp.NewServer(&mcp.Implementation{Name: "go-mcp-example"}, nil)
+	// Authenticate callers of the HTTP transport, if enabled
+	authenticator, err := auth.New(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("failed to configure authentication: %w", err)
+	}
 
 	// Create shared API client for all analytics tools
 	apiClient := tools.NewAPIClient(cfg.APIBaseURL, cfg.APIKey)
+	apiClient.Retry = tools.NewRetryPolicy(cfg)
+	apiClient.Limiter = tools.NewRateLimiterFromConfig(cfg)
+
+	// Attach the response cache, if enabled
+	cache, err := tools.NewCache(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create response cache: %w", err)
+	}
+	apiClient.Cache = cache
+
+	// Trace requests, if enabled, exporting the buffered spans on the way out
+	tracer := newTracer(ctx, cfg, logger)
+	defer shutdownTracer(tracer, logger)
+	apiClient.Tracer = tracer
+
+	// Collect metrics, if enabled
+	var m *metrics.Metrics
+	if cfg.MetricsAddr != "" {
+		m = metrics.New()
+		apiClient.Metrics = m
+	}
 
 	// Create tools, prompts, and resources with logger, config, and shared API client
 	reportsTool := tools.NewReportsTool(logger, cfg, apiClient)
-	reportPrompts := prompts.NewReportPrompts(logger)
-	resourceHandler := resources.NewResourceHandler(logger)
+	reportPrompts, err := prompts.NewReportPromptsWithDir(logger, cfg.PromptsDir)
+	if err != nil {
+		return fmt.Errorf("failed to load prompt templates: %w", err)
+	}
+	resourceHandler := resources.NewResourceHandlerWithDir(logger, cfg.ResourcesDir)
+	subscriptions := resources.NewSubscriptions(logger, tools.RealtimeResourceURI)
+	completer := tools.NewCompleter(reportsTool, reportPrompts.ArgumentType)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "go-mcp-example"}, &mcp.ServerOptions{
+		CompletionHandler: completer.Complete,
+	})
+
+	// Track in-flight requests so that shutdown can drain them
+	drainer := lifecycle.NewDrainer()
+	server.AddReceivingMiddleware(drainer.Middleware)
 
 	// Register tools
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "get_report",
-		Description: tools.GetReportToolDescription,
-	}, reportsTool.GetReport)
-
-	// Register prompts
-	server.AddPrompt(&mcp.Prompt{
-		Name:        "analyze-traffic",
-		Description: "Analyze website traffic patterns and trends with guided data exploration",
-	}, reportPrompts.AnalyzeTrafficPrompt)
-
-	server.AddPrompt(&mcp.Prompt{
-		Name:        "compare-reports",
-		Description: "Compare and analyze two different report types for insights",
-	}, reportPrompts.CompareReportsPrompt)
-
-	server.AddPrompt(&mcp.Prompt{
-		Name:        "monthly-report",
-		Description: "Generate comprehensive monthly analytics report",
-	}, reportPrompts.MonthlyReportPrompt)
-
-	server.AddPrompt(&mcp.Prompt{
-		Name:        "realtime-insights",
-		Description: "Get real-time website analytics and insights",
-	}, reportPrompts.RealTimeInsightsPrompt)
+	toolNames := registerTools(server, reportsTool, tracer)
+
+	// Record tool calls and sessions, and serve the metrics on their own listener
+	if m != nil {
+		instrumentServer(server, m, toolNames)
+		stopMetrics, metricsErr := serveMetrics(cfg.MetricsAddr, m, logger)
+		if metricsErr != nil {
+			return metricsErr
+		}
+		defer stopMetrics()
+	}
+
+	// Trace requests, outermost so that the spans cover the other middleware
+	if tracer != nil {
+		server.AddReceivingMiddleware(tracer.Middleware)
+	}
+
+	// Register prompts from the built-in and override templates
+	reportPrompts.Register(server)
+	if cfg.PromptsDir != "" && cfg.PromptsWatchInterval > 0 {
+		go reportPrompts.WatchTemplates(ctx, server, cfg.PromptsWatchInterval)
+	}
 
 	// Register resources
+	if err = registerResources(ctx, cfg, server, reportsTool, resourceHandler); err != nil {
+		return err
+	}
+
+	// Poll the realtime report for subscribed clients
+	if cfg.RealtimePollInterval > 0 {
+		poller := tools.NewRealtimePoller(reportsTool, subscriptions, cfg.RealtimePollInterval)
+		go poller.Run(ctx)
+	}
+
+	if cfg.HTTPAddr != "" {
+		handler := newHTTPHandler(cfg, logger, server, subscriptions, apiClient, authenticator, drainer)
+		return serveHTTP(ctx, cfg, logger, handler, drainer)
+	}
+	return serveStdio(ctx, cfg, logger, server, drainer, subscriptions)
+}
+
+// registerTools registers the report tools, traced by tracer if set, and returns their names.
+func registerTools(server *mcp.Server, reportsTool *tools.ReportsTool, tracer *tracing.Tracer) []string {
+	getReport := &mcp.Tool{Name: "get_report", Description: tools.GetReportToolDescription}
+	mcp.AddTool(server, getReport, tracing.ToolHandler(tracer, getReport.Name, reportsTool.GetReport))
+
+	aggregateReport := &mcp.Tool{Name: "aggregate_report", Description: tools.AggregateReportToolDescription}
+	mcp.AddTool(server, aggregateReport,
+		tracing.ToolHandler(tracer, aggregateReport.Name, reportsTool.AggregateReport))
+
+	comparePeriods := &mcp.Tool{Name: "compare_periods", Description: tools.ComparePeriodsToolDescription}
+	mcp.AddTool(server, comparePeriods, tracing.ToolHandler(tracer, comparePeriods.Name, reportsTool.ComparePeriods))
+
+	detectAnomalies := &mcp.Tool{Name: "detect_anomalies", Description: tools.DetectAnomaliesToolDescription}
+	mcp.AddTool(server, detectAnomalies,
+		tracing.ToolHandler(tracer, detectAnomalies.Name, reportsTool.DetectAnomalies))
+
+	return []string{getReport.Name, aggregateReport.Name, comparePeriods.Name, detectAnomalies.Name}
+}
+
+// registerResources registers the embedded info, the report catalog and the report data resources,
+// and the files of the resources directory, which is watched for added and removed files.
+func registerResources(ctx context.Context, cfg *config.Config, server *mcp.Server, reportsTool *tools.ReportsTool,
+	resourceHandler *resources.ResourceHandler) error {
 	server.AddResource(&mcp.Resource{
 		Name:     "info",
 		MIMEType: "text/plain",
 		URI:      "embedded:info",
 	}, resourceHandler.HandleEmbeddedResource)
 
-	if cfg.HTTPAddr != "" {
-		handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
-			return server
-		}, nil)
-		logger.Info("MCP handler starting", "transport", "http", "address", cfg.HTTPAddr)
-
-		// Create HTTP server with timeouts
-		httpServer := &http.Server{
-			Addr:         cfg.HTTPAddr,
-			Handler:      handler,
-			ReadTimeout:  readTimeoutSeconds * time.Second,
-			WriteTimeout: writeTimeoutSeconds * time.Second,
-			IdleTimeout:  idleTimeoutSeconds * time.Second,
-		}
+	server.AddResource(&mcp.Resource{
+		Name:        "reports",
+		Description: "Catalog of all report types with their dimension fields, metric fields and filters",
+		MIMEType:    "application/json",
+		URI:         resources.ReportCatalogURI,
+	}, resourceHandler.HandleReportCatalog)
+
+	for _, report := range models.ReportCatalog() {
+		server.AddResource(&mcp.Resource{
+			Name:        "report-" + report.Name.String(),
+			Description: report.Description,
+			MIMEType:    "application/json",
+			URI:         resources.ReportResourceURI(report.Name),
+		}, resourceHandler.HandleReportCatalog)
+	}
+
+	// The realtime data is listed as a resource of its own so that clients can subscribe to its updates
+	server.AddResource(&mcp.Resource{
+		Name:        "realtime-data",
+		Description: "Live realtime report data; subscribe to be notified when active visitors change",
+		MIMEType:    "application/json",
+		URI:         tools.RealtimeResourceURI,
+	}, reportsTool.ReadReportResource)
+
+	// Resource templates read live report data
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "report-data",
+		Description: "One page of report data as JSON",
+		MIMEType:    "application/json",
+		URITemplate: tools.ReportDataURITemplate,
+	}, reportsTool.ReadReportResource)
 
-		if httpErr := httpServer.ListenAndServe(); httpErr != nil {
-			logger.Error("HTTP server failed", "error", httpErr)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "agency-report-data",
+		Description: "One page of report data for a single agency as JSON",
+		MIMEType:    "application/json",
+		URITemplate: tools.AgencyReportDataURITemplate,
+	}, reportsTool.ReadReportResource)
+
+	if cfg.ResourcesDir != "" {
+		if err := resourceHandler.SyncFileResources(server); err != nil {
+			return fmt.Errorf("failed to load resources directory '%s': %w", cfg.ResourcesDir, err)
+		}
+		if cfg.ResourcesWatchInterval > 0 {
+			go resourceHandler.WatchFileResources(ctx, server, cfg.ResourcesWatchInterval)
 		}
+	}
+	return nil
+}
+
+// newHTTPHandler serves the MCP endpoint, with resource subscriptions and behind the authenticator if
+// set, next to the probe and version endpoints, which stay unauthenticated for orchestrators. The
+// readiness probe fails once the drainer drains.
+func newHTTPHandler(cfg *config.Config, logger *slog.Logger, server *mcp.Server,
+	subscriptions *resources.Subscriptions, apiClient *tools.APIClient, authenticator *auth.Authenticator,
+	drainer *lifecycle.Drainer) http.Handler {
+	mux := http.NewServeMux()
+	lifecycle.NewHealth(logger, cfg, lifecycle.ReadBuildInfo(version), apiClient.Ping, drainer).Register(mux)
+
+	var handler http.Handler = subscriptions.StreamableHTTPHandler(server)
+	if authenticator != nil {
+		handler = authenticator.Middleware(handler)
 	} else {
-		logger.Info("MCP handler starting", "transport", "stdio")
-		t := mcp.NewLoggingTransport(mcp.NewStdioTransport(), os.Stderr)
-		if runErr := server.Run(context.Background(), t); runErr != nil {
-			logger.Error("Server failed", "error", runErr)
+		logger.Warn("HTTP transport is unauthenticated, anyone who can reach it can spend the API key; " +
+			"set AUTH_TOKENS, AUTH_TOKENS_FILE or AUTH_JWKS_FILE to require credentials")
+	}
+	mux.Handle("/", handler)
+	return mux
+}
+
+// serveHTTP serves MCP over HTTP until ctx is cancelled, then stops accepting connections, drains
+// the in-flight requests and closes the open streams.
+func serveHTTP(ctx context.Context, cfg *config.Config, logger *slog.Logger, handler http.Handler,
+	drainer *lifecycle.Drainer) error {
+	logger.Info("MCP handler starting", "transport", "http", "address", cfg.HTTPAddr,
+		"readiness_ping_ttl", cfg.ReadinessPingTTL)
+
+	// Request contexts are cancelled after the drain to end the streams that clients keep open
+	streamCtx, cancelStreams := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelStreams()
+
+	// Create HTTP server with timeouts
+	httpServer := &http.Server{
+		Addr:         cfg.HTTPAddr,
+		Handler:      handler,
+		ReadTimeout:  readTimeoutSeconds * time.Second,
+		WriteTimeout: writeTimeoutSeconds * time.Second,
+		IdleTimeout:  idleTimeoutSeconds * time.Second,
+		BaseContext:  func(net.Listener) context.Context { return streamCtx },
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("HTTP server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	logger.Info("Shutting down MCP server", "timeout", cfg.ShutdownTimeout)
+	drainCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		shutdownErr <- httpServer.Shutdown(drainCtx)
+	}()
+	if err := drainer.Drain(drainCtx); err != nil {
+		logger.Warn("In-flight requests did not finish in time and were cancelled", "error", err)
+	}
+	cancelStreams()
+
+	if err := <-shutdownErr; err != nil {
+		logger.Warn("Closing connections that did not finish in time", "error", err)
+		return httpServer.Close()
+	}
+	return nil
+}
+
+// newTracer creates a tracer for the configured exporter, or returns nil if tracing is disabled.
+// Failures to create or use the exporter are logged, since tracing must not affect serving.
+func newTracer(ctx context.Context, cfg *config.Config, logger *slog.Logger) *tracing.Tracer {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch strings.ToLower(cfg.TracingExporter) {
+	case "otlp":
+		logger.Info("Tracing enabled", "exporter", "otlp", "endpoint", cfg.OTLPEndpoint)
+		exporter, err = tracing.NewOTLPExporter(ctx, cfg.OTLPEndpoint)
+	case "stdout":
+		// The stdio transport speaks MCP on stdout, so spans are written to stderr instead
+		out := os.Stdout
+		if cfg.HTTPAddr == "" {
+			out = os.Stderr
 		}
+		logger.Info("Tracing enabled", "exporter", "stdout", "file", out.Name())
+		exporter, err = tracing.NewWriterExporter(out)
+	default:
+		return nil
+	}
+	if err != nil {
+		logger.Warn("Tracing disabled, failed to create the exporter", "error", err)
+		return nil
+	}
+
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("Failed to export spans", "error", err)
+	}))
+	return tracing.NewTracer(exporter, tracing.Resource{ServiceName: "go-mcp-example", ServiceVersion: version})
+}
+
+// shutdownTracer exports the spans still buffered by the tracer, if any.
+func shutdownTracer(tracer *tracing.Tracer, logger *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), tracerShutdownTimeout)
+	defer cancel()
+	if err := tracer.Shutdown(ctx); err != nil {
+		logger.Warn("Failed to export the remaining spans", "error", err)
+	}
+}
+
+// instrumentServer records the tool calls and the active sessions of server in m.
+func instrumentServer(server *mcp.Server, m *metrics.Metrics, toolNames []string) {
+	server.AddReceivingMiddleware(m.Middleware(toolNames...))
+	m.TrackSessions(func() int {
+		sessions := 0
+		for range server.Sessions() {
+			sessions++
+		}
+		return sessions
+	})
+}
+
+// serveMetrics serves the metrics at /metrics on their own listener, so that they are available with
+// either transport, and returns a function that stops serving them.
+func serveMetrics(addr string, m *metrics.Metrics, logger *slog.Logger) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for metrics: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", m)
+	metricsServer := &http.Server{
+		Handler:      mux,
+		ReadTimeout:  readTimeoutSeconds * time.Second,
+		WriteTimeout: writeTimeoutSeconds * time.Second,
+		IdleTimeout:  idleTimeoutSeconds * time.Second,
+	}
+
+	logger.Info("Metrics endpoint starting", "address", listener.Addr().String(), "path", "/metrics")
+	go func() {
+		if serveErr := metricsServer.Serve(listener); !errors.Is(serveErr, http.ErrServerClosed) {
+			logger.Error("Metrics server failed", "error", serveErr)
+		}
+	}()
+	return func() { _ = metricsServer.Close() }, nil
+}
+
+// serveStdio serves MCP over stdin/stdout until the client disconnects or ctx is cancelled, in
+// which case the in-flight requests are drained before the session is closed.
+func serveStdio(ctx context.Context, cfg *config.Config, logger *slog.Logger, server *mcp.Server,
+	drainer *lifecycle.Drainer, subscriptions *resources.Subscriptions) error {
+	logger.Info("MCP handler starting", "transport", "stdio")
+	t := subscriptions.Transport(mcp.NewLoggingTransport(mcp.NewStdioTransport(), os.Stderr))
+
+	// The session outlives ctx until the in-flight requests are drained
+	sessionCtx, closeSession := context.WithCancel(context.WithoutCancel(ctx))
+	defer closeSession()
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- server.Run(sessionCtx, t)
+	}()
+
+	select {
+	case err := <-runErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("Shutting down MCP server", "timeout", cfg.ShutdownTimeout)
+	drainCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := drainer.Drain(drainCtx); err != nil {
+		logger.Warn("In-flight requests did not finish in time and were cancelled", "error", err)
 	}
+	// A blocked read of stdin cannot be interrupted, so the closing session is not waited for
+	closeSession()
+	return nil
 }
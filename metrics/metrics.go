@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Outcomes of a tool call.
+const (
+	OutcomeSuccess   = "success"
+	OutcomeToolError = "tool_error" // The tool ran and reported an error result to the model
+	OutcomeError     = "error"      // The call failed, e.g. because of invalid arguments
+)
+
+// StatusError labels upstream requests that failed without an HTTP response.
+const StatusError = "error"
+
+// methodCallTool is the MCP method of tool calls.
+const methodCallTool = "tools/call"
+
+// unknownTool labels the calls of tools the server does not have.
+const unknownTool = "unknown"
+
+var (
+	// latencyBuckets are the upper bounds, in seconds, of the upstream request latency buckets.
+	latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+	// pageBuckets are the upper bounds of the pagination depth buckets.
+	pageBuckets = []float64{1, 2, 3, 5, 10, 20, 50, 100}
+)
+
+// Metrics are the metrics collected by the MCP server. A nil *Metrics records nothing, so that
+// components can take one optionally.
+type Metrics struct {
+	registry *Registry
+
+	toolCalls       *CounterVec
+	apiDuration     *HistogramVec
+	paginationPages *HistogramVec
+	cacheLookups    *CounterVec
+}
+
+// New creates the server metrics in a new registry.
+func New() *Metrics {
+	r := NewRegistry()
+	return &Metrics{
+		registry: r,
+		toolCalls: r.NewCounterVec("mcp_tool_calls_total",
+			"MCP tool calls by tool name and outcome.", "tool", "outcome"),
+		apiDuration: r.NewHistogramVec("dap_api_request_duration_seconds",
+			"Latency of DAP API requests by report name and HTTP status.", latencyBuckets, "report", "status"),
+		paginationPages: r.NewHistogramVec("dap_pagination_pages",
+			"Pages read by multi-page report fetches.", pageBuckets),
+		cacheLookups: r.NewCounterVec("dap_cache_lookups_total",
+			"Response cache lookups by result, hit or miss.", "result"),
+	}
+}
+
+// TrackSessions exposes the number of active MCP sessions, as returned by count, as a gauge.
+func (m *Metrics) TrackSessions(count func() int) {
+	if m == nil {
+		return
+	}
+	m.registry.NewGaugeFunc("mcp_active_sessions", "Active MCP sessions.", func() float64 {
+		return float64(count())
+	})
+}
+
+// ObserveToolCall records a tool call.
+func (m *Metrics) ObserveToolCall(tool, outcome string) {
+	if m == nil {
+		return
+	}
+	m.toolCalls.Inc(tool, outcome)
+}
+
+// ObserveAPIRequest records the latency of an upstream request for a report. status is the HTTP
+// status code, or 0 if the request failed without a response.
+func (m *Metrics) ObserveAPIRequest(report string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	statusLabel := StatusError
+	if status != 0 {
+		statusLabel = strconv.Itoa(status)
+	}
+	m.apiDuration.Observe(duration.Seconds(), report, statusLabel)
+}
+
+// ObservePagination records the number of pages read by a multi-page fetch.
+func (m *Metrics) ObservePagination(pages int) {
+	if m == nil {
+		return
+	}
+	m.paginationPages.Observe(float64(pages))
+}
+
+// ObserveCacheLookup records a response cache lookup.
+func (m *Metrics) ObserveCacheLookup(hit bool) {
+	if m == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.Inc(result)
+}
+
+// Middleware records the tool calls handled by the server. Calls of tools other than the given
+// ones are recorded as unknownTool, so that clients cannot create arbitrary series.
+func (m *Metrics) Middleware(tools ...string) mcp.Middleware[*mcp.ServerSession] {
+	known := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		known[tool] = true
+	}
+
+	return func(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
+		return func(ctx context.Context, ss *mcp.ServerSession, method string, params mcp.Params) (mcp.Result, error) {
+			result, err := next(ctx, ss, method, params)
+			if method == methodCallTool {
+				m.ObserveToolCall(toolName(params, known), toolOutcome(result, err))
+			}
+			return result, err
+		}
+	}
+}
+
+// toolName returns the name of the called tool, or unknownTool if it is not a known one.
+func toolName(params mcp.Params, known map[string]bool) string {
+	if p, ok := params.(*mcp.CallToolParamsFor[json.RawMessage]); ok && known[p.Name] {
+		return p.Name
+	}
+	return unknownTool
+}
+
+// toolOutcome classifies the result of a tool call.
+func toolOutcome(result mcp.Result, err error) string {
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	} else if r, ok := result.(*mcp.CallToolResult); ok && r.IsError {
+		outcome = OutcomeToolError
+	}
+	return outcome
+}
+
+// ServeHTTP serves the metrics to a Prometheus scrape.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.registry.ServeHTTP(w, r)
+}
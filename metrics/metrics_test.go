@@ -0,0 +1,106 @@
+package metrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/metrics"
+)
+
+// scrape returns the metrics as served to Prometheus.
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	return rec.Body.String()
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New()
+	// The fake tool fails without arguments and reports a tool error for empty ones
+	handler := m.Middleware("get_report")(func(_ context.Context, _ *mcp.ServerSession, _ string,
+		params mcp.Params) (mcp.Result, error) {
+		arguments := params.(*mcp.CallToolParamsFor[json.RawMessage]).Arguments
+		if arguments == nil {
+			return nil, errors.New("invalid arguments")
+		}
+		return &mcp.CallToolResult{IsError: string(arguments) == "{}"}, nil
+	})
+
+	calls := []*mcp.CallToolParamsFor[json.RawMessage]{
+		{Name: "get_report", Arguments: json.RawMessage(`{"report_name":"os"}`)},
+		{Name: "get_report", Arguments: json.RawMessage(`{"report_name":"os"}`)},
+		{Name: "get_report", Arguments: json.RawMessage(`{}`)},
+		{Name: "get_report"},
+		{Name: "no_such_tool", Arguments: json.RawMessage(`{}`)},
+	}
+	for _, params := range calls {
+		_, _ = handler(context.Background(), nil, "tools/call", params)
+	}
+	_, _ = handler(context.Background(), nil, "tools/list", &mcp.CallToolParamsFor[json.RawMessage]{})
+
+	out := scrape(t, m)
+	for _, want := range []string{
+		`mcp_tool_calls_total{tool="get_report",outcome="success"} 2`,
+		`mcp_tool_calls_total{tool="get_report",outcome="tool_error"} 1`,
+		`mcp_tool_calls_total{tool="get_report",outcome="error"} 1`,
+		`mcp_tool_calls_total{tool="unknown",outcome="tool_error"} 1`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("Metrics are missing %s:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "mcp_tool_calls_total{") != 4 {
+		t.Errorf("Metrics have unexpected tool call series:\n%s", out)
+	}
+}
+
+func TestMetrics_Observe(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New()
+	m.TrackSessions(func() int { return 2 })
+	m.ObserveAPIRequest("realtime", http.StatusOK, 300*time.Millisecond)
+	m.ObserveAPIRequest("realtime", 0, time.Second)
+	m.ObservePagination(3)
+	m.ObserveCacheLookup(true)
+	m.ObserveCacheLookup(false)
+	m.ObserveCacheLookup(true)
+
+	out := scrape(t, m)
+	for _, want := range []string{
+		`dap_api_request_duration_seconds_bucket{report="realtime",status="200",le="0.5"} 1`,
+		`dap_api_request_duration_seconds_count{report="realtime",status="200"} 1`,
+		`dap_api_request_duration_seconds_count{report="realtime",status="error"} 1`,
+		`dap_pagination_pages_bucket{le="2"} 0`,
+		`dap_pagination_pages_bucket{le="3"} 1`,
+		`dap_cache_lookups_total{result="hit"} 2`,
+		`dap_cache_lookups_total{result="miss"} 1`,
+		`mcp_active_sessions 2`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("Metrics are missing %s:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	t.Parallel()
+
+	// A nil *Metrics records nothing and must not panic
+	var m *metrics.Metrics
+	m.TrackSessions(func() int { return 1 })
+	m.ObserveToolCall("get_report", metrics.OutcomeSuccess)
+	m.ObserveAPIRequest("realtime", http.StatusOK, time.Second)
+	m.ObservePagination(1)
+	m.ObserveCacheLookup(true)
+}
@@ -0,0 +1,80 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rameshsunkara/go-mcp-example/metrics"
+)
+
+func TestRegistry_Write(t *testing.T) {
+	t.Parallel()
+
+	r := metrics.NewRegistry()
+	requests := r.NewCounterVec("requests_total", "Requests by path.", "path")
+	latency := r.NewHistogramVec("latency_seconds", "Request latency.", []float64{1, 0.1})
+	r.NewGaugeFunc("connections", "Open connections.", func() float64 { return 3 })
+
+	requests.Inc("/b")
+	requests.Add(2, "/a")
+	requests.Inc(`/"quoted"`)
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(2)
+
+	var out strings.Builder
+	if err := r.Write(&out); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	expected := `# HELP requests_total Requests by path.
+# TYPE requests_total counter
+requests_total{path="/\"quoted\""} 1
+requests_total{path="/a"} 2
+requests_total{path="/b"} 1
+# HELP latency_seconds Request latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 1
+latency_seconds_bucket{le="1"} 2
+latency_seconds_bucket{le="+Inf"} 3
+latency_seconds_sum 2.55
+latency_seconds_count 3
+# HELP connections Open connections.
+# TYPE connections gauge
+connections 3
+`
+	if out.String() != expected {
+		t.Errorf("Write() =\n%s\nwant\n%s", out.String(), expected)
+	}
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	r := metrics.NewRegistry()
+	r.NewCounterVec("requests_total", "Requests.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want the text exposition format", got)
+	}
+	if !strings.Contains(rec.Body.String(), "\nrequests_total 1\n") {
+		t.Errorf("Body = %q, want the requests_total sample", rec.Body.String())
+	}
+}
+
+func TestCounterVec_LabelMismatch(t *testing.T) {
+	t.Parallel()
+
+	counter := metrics.NewRegistry().NewCounterVec("requests_total", "Requests.", "path")
+	defer func() {
+		if recover() == nil {
+			t.Error("Inc() with missing label values expected panic but got none")
+		}
+	}()
+	counter.Inc()
+}
@@ -0,0 +1,217 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AggregateFunc is a summary function applied to a metric within a group.
+type AggregateFunc string
+
+const (
+	AggregateSum   AggregateFunc = "sum"
+	AggregateAvg   AggregateFunc = "avg"
+	AggregateMin   AggregateFunc = "min"
+	AggregateMax   AggregateFunc = "max"
+	AggregateCount AggregateFunc = "count"
+)
+
+// IsValid checks if the aggregate function is supported.
+func (f AggregateFunc) IsValid() bool {
+	switch f {
+	case AggregateSum, AggregateAvg, AggregateMin, AggregateMax, AggregateCount:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetAllAggregateFuncs returns all supported aggregate functions.
+func GetAllAggregateFuncs() []AggregateFunc {
+	return []AggregateFunc{AggregateSum, AggregateAvg, AggregateMin, AggregateMax, AggregateCount}
+}
+
+// Aggregation describes how report rows are grouped and summarized.
+type Aggregation struct {
+	GroupBy   string
+	Metrics   []string
+	Functions []AggregateFunc
+}
+
+// Validate validates the grouping field, metrics and functions of the aggregation.
+func (a *Aggregation) Validate() error {
+	if a.GroupBy == "" {
+		return errors.New("group_by is required")
+	}
+	if !IsDimensionField(a.GroupBy) {
+		return fmt.Errorf("invalid group_by field '%s'. Valid fields: %s",
+			a.GroupBy, strings.Join(DimensionFieldNames(), ", "))
+	}
+
+	if len(a.Metrics) == 0 {
+		return errors.New("at least one metric is required")
+	}
+	for _, metric := range a.Metrics {
+		if !IsMetricField(metric) {
+			return fmt.Errorf("invalid metric '%s'. Valid metrics: %s",
+				metric, strings.Join(MetricFieldNames(), ", "))
+		}
+	}
+
+	if len(a.Functions) == 0 {
+		return errors.New("at least one function is required")
+	}
+	for _, function := range a.Functions {
+		if !function.IsValid() {
+			return fmt.Errorf("invalid function '%s'. Valid functions: %v", function, GetAllAggregateFuncs())
+		}
+	}
+
+	return nil
+}
+
+// Columns returns the names of the value columns produced by the aggregation, such as "sum_visits".
+// Group sizes are always reported in AggregateRow.Count, so count adds no value column.
+func (a *Aggregation) Columns() []string {
+	var columns []string
+	for _, metric := range a.Metrics {
+		for _, function := range a.Functions {
+			if function == AggregateCount {
+				continue
+			}
+			column := string(function) + "_" + metric
+			if !slices.Contains(columns, column) {
+				columns = append(columns, column)
+			}
+		}
+	}
+	return columns
+}
+
+// Apply groups the rows and computes the aggregation for every group. Groups are ordered by
+// the first value column (or by count when there is none) in descending order.
+func (a *Aggregation) Apply(rows []Reports) []AggregateRow {
+	groups := make(map[string]*accumulator)
+	var order []string
+	for i := range rows {
+		group, _ := rows[i].Dimension(a.GroupBy)
+		acc, ok := groups[group]
+		if !ok {
+			acc = newAccumulator()
+			groups[group] = acc
+			order = append(order, group)
+		}
+		acc.add(&rows[i], a.Metrics)
+	}
+
+	result := make([]AggregateRow, 0, len(order))
+	for _, group := range order {
+		acc := groups[group]
+		row := AggregateRow{Group: group, Count: acc.count, Values: make(map[string]float64)}
+		for _, metric := range a.Metrics {
+			for _, function := range a.Functions {
+				if function != AggregateCount {
+					row.Values[string(function)+"_"+metric] = acc.value(function, metric)
+				}
+			}
+		}
+		result = append(result, row)
+	}
+
+	columns := a.Columns()
+	slices.SortStableFunc(result, func(x, y AggregateRow) int {
+		if len(columns) > 0 {
+			if c := compareDesc(x.Values[columns[0]], y.Values[columns[0]]); c != 0 {
+				return c
+			}
+		}
+		if c := compareDesc(float64(x.Count), float64(y.Count)); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Group, y.Group)
+	})
+
+	return result
+}
+
+// accumulator collects the running totals of a single group.
+type accumulator struct {
+	count int
+	sum   map[string]float64
+	min   map[string]float64
+	max   map[string]float64
+}
+
+// newAccumulator creates an empty accumulator.
+func newAccumulator() *accumulator {
+	return &accumulator{
+		sum: make(map[string]float64),
+		min: make(map[string]float64),
+		max: make(map[string]float64),
+	}
+}
+
+// add records the metric values of a report row.
+func (acc *accumulator) add(row *Reports, metrics []string) {
+	for _, metric := range metrics {
+		value, _ := row.Metric(metric)
+		acc.sum[metric] += value
+		if acc.count == 0 || value < acc.min[metric] {
+			acc.min[metric] = value
+		}
+		if acc.count == 0 || value > acc.max[metric] {
+			acc.max[metric] = value
+		}
+	}
+	acc.count++
+}
+
+// value returns the result of applying function to the collected values of metric.
+func (acc *accumulator) value(function AggregateFunc, metric string) float64 {
+	switch function {
+	case AggregateSum:
+		return acc.sum[metric]
+	case AggregateAvg:
+		return acc.sum[metric] / float64(acc.count)
+	case AggregateMin:
+		return acc.min[metric]
+	case AggregateMax:
+		return acc.max[metric]
+	case AggregateCount:
+		return float64(acc.count)
+	default:
+		return 0
+	}
+}
+
+// compareDesc compares two values for descending order.
+func compareDesc(x, y float64) int {
+	switch {
+	case x > y:
+		return -1
+	case x < y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AggregateRow is the summary of a single group of report rows.
+type AggregateRow struct {
+	Group  string             `json:"group" jsonschema:"Value of the group_by field"`
+	Count  int                `json:"count" jsonschema:"Number of report rows in the group"`
+	Values map[string]float64 `json:"values" jsonschema:"Aggregated values keyed by column, e.g. sum_visits"`
+}
+
+// AggregateResponse represents the response of an aggregated report.
+type AggregateResponse struct {
+	ReportName string         `json:"report_name" jsonschema:"Name of the aggregated report"`
+	GroupBy    string         `json:"group_by" jsonschema:"Field the rows were grouped by"`
+	Columns    []string       `json:"columns" jsonschema:"Names of the aggregated value columns"`
+	Rows       []AggregateRow `json:"rows" jsonschema:"One row per group, largest first"`
+	Records    int            `json:"records" jsonschema:"Number of report rows that were aggregated"`
+	Truncated  bool           `json:"truncated" jsonschema:"Whether more records were available beyond max_records"`
+	Error      *Error         `json:"error,omitempty" jsonschema:"Error information if request failed"`
+}
@@ -0,0 +1,118 @@
+package models_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+func TestAggregation_Validate(t *testing.T) {
+	t.Parallel()
+
+	sum := []models.AggregateFunc{models.AggregateSum}
+
+	tests := []struct {
+		name        string
+		aggregation models.Aggregation
+		expectErr   bool
+		errMsg      string
+	}{
+		{
+			name:        "valid aggregation",
+			aggregation: models.Aggregation{GroupBy: "browser", Metrics: []string{"visits", "bounce_rate"}, Functions: sum},
+			expectErr:   false,
+		},
+		{
+			name:        "missing group_by",
+			aggregation: models.Aggregation{Metrics: []string{"visits"}, Functions: sum},
+			expectErr:   true,
+			errMsg:      "group_by is required",
+		},
+		{
+			name:        "metric used as group_by",
+			aggregation: models.Aggregation{GroupBy: "visits", Metrics: []string{"visits"}, Functions: sum},
+			expectErr:   true,
+			errMsg:      "invalid group_by field 'visits'",
+		},
+		{
+			name:        "missing metrics",
+			aggregation: models.Aggregation{GroupBy: "device", Functions: sum},
+			expectErr:   true,
+			errMsg:      "at least one metric is required",
+		},
+		{
+			name:        "dimension used as metric",
+			aggregation: models.Aggregation{GroupBy: "device", Metrics: []string{"browser"}, Functions: sum},
+			expectErr:   true,
+			errMsg:      "invalid metric 'browser'",
+		},
+		{
+			name: "unknown function",
+			aggregation: models.Aggregation{
+				GroupBy: "device", Metrics: []string{"visits"}, Functions: []models.AggregateFunc{"median"},
+			},
+			expectErr: true,
+			errMsg:    "invalid function 'median'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.aggregation.Validate()
+
+			validateTestResult(t, err, tt.expectErr, tt.errMsg)
+		})
+	}
+}
+
+func TestAggregation_Apply(t *testing.T) {
+	t.Parallel()
+
+	rows := []models.Reports{
+		{Browser: "Safari", Visits: 10, BounceRate: 0.5},
+		{Browser: "Chrome", Visits: 30, BounceRate: 0.2},
+		{Browser: "Safari", Visits: 20, BounceRate: 0.3},
+		{Browser: "Chrome", Visits: 5, BounceRate: 0.4},
+		{Visits: 1},
+	}
+
+	aggregation := models.Aggregation{
+		GroupBy: "browser",
+		Metrics: []string{"visits", "bounce_rate"},
+		Functions: []models.AggregateFunc{
+			models.AggregateSum, models.AggregateMin, models.AggregateMax, models.AggregateCount,
+		},
+	}
+
+	expectedColumns := []string{
+		"sum_visits", "min_visits", "max_visits", "sum_bounce_rate", "min_bounce_rate", "max_bounce_rate",
+	}
+	if columns := aggregation.Columns(); !reflect.DeepEqual(columns, expectedColumns) {
+		t.Errorf("Columns() = %v, want %v", columns, expectedColumns)
+	}
+
+	result := aggregation.Apply(rows)
+	if len(result) != 3 {
+		t.Fatalf("Apply() returned %d groups, want 3", len(result))
+	}
+
+	// Groups are ordered by sum_visits, largest first
+	expectedGroups := []string{"Chrome", "Safari", ""}
+	for i, group := range expectedGroups {
+		if result[i].Group != group {
+			t.Errorf("Group %d = %q, want %q", i, result[i].Group, group)
+		}
+	}
+
+	safari := result[1]
+	if safari.Count != 2 || safari.Values["sum_visits"] != 30 || safari.Values["min_visits"] != 10 ||
+		safari.Values["max_visits"] != 20 || safari.Values["max_bounce_rate"] != 0.5 {
+		t.Errorf("Safari group = %+v, want count 2, visits 30/10/20 and max bounce rate 0.5", safari)
+	}
+	if _, ok := safari.Values["count_visits"]; ok {
+		t.Error("count should not produce a value column")
+	}
+}
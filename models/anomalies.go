@@ -0,0 +1,211 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// AnomalyMethod is the statistic used to decide whether a day is anomalous.
+type AnomalyMethod string
+
+const (
+	AnomalyZScore AnomalyMethod = "zscore"
+	AnomalyIQR    AnomalyMethod = "iqr"
+)
+
+// Default band widths: a z-score of 3 standard deviations and Tukey's 1.5 interquartile ranges.
+const (
+	defaultZScoreThreshold = 3
+	defaultIQRThreshold    = 1.5
+	quartileLower          = 0.25
+	quartileMedian         = 0.5
+	quartileUpper          = 0.75
+)
+
+// IsValid checks if the anomaly detection method is supported.
+func (m AnomalyMethod) IsValid() bool {
+	switch m {
+	case AnomalyZScore, AnomalyIQR:
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultThreshold returns the conventional band width of the method.
+func (m AnomalyMethod) DefaultThreshold() float64 {
+	if m == AnomalyIQR {
+		return defaultIQRThreshold
+	}
+	return defaultZScoreThreshold
+}
+
+// GetAllAnomalyMethods returns all supported anomaly detection methods.
+func GetAllAnomalyMethods() []AnomalyMethod {
+	return []AnomalyMethod{AnomalyZScore, AnomalyIQR}
+}
+
+// SeriesPoint is the total of a metric on a single day.
+type SeriesPoint struct {
+	Date  string  `json:"date" jsonschema:"Day of the value (YYYY-MM-DD)"`
+	Value float64 `json:"value" jsonschema:"Total of the metric on that day"`
+}
+
+// DailySeries sums the metric of the rows per day. Days between the first and the last date
+// without any rows are included with a value of zero, so the series has no gaps.
+func DailySeries(rows []Reports, metric string) ([]SeriesPoint, error) {
+	if !IsMetricField(metric) {
+		return nil, fmt.Errorf("invalid metric '%s'", metric)
+	}
+
+	totals := make(map[time.Time]float64)
+	var first, last time.Time
+	for i := range rows {
+		date, err := rows[i].ParseDate()
+		if err != nil {
+			return nil, fmt.Errorf("row %d has an invalid date '%s': %w", rows[i].ID, rows[i].Date, err)
+		}
+		value, _ := rows[i].Metric(metric)
+		totals[date] += value
+		if first.IsZero() || date.Before(first) {
+			first = date
+		}
+		if last.IsZero() || date.After(last) {
+			last = date
+		}
+	}
+
+	if len(totals) == 0 {
+		return []SeriesPoint{}, nil
+	}
+
+	var series []SeriesPoint
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		series = append(series, SeriesPoint{Date: date.Format(DateLayout), Value: totals[date]})
+	}
+	return series, nil
+}
+
+// Anomaly is a day whose value falls outside the band expected from the preceding days.
+type Anomaly struct {
+	Date      string  `json:"date" jsonschema:"Day of the anomaly (YYYY-MM-DD)"`
+	Actual    float64 `json:"actual" jsonschema:"Observed value"`
+	Expected  float64 `json:"expected" jsonschema:"Expected value: mean (zscore) or median (iqr) of the window"`
+	Lower     float64 `json:"lower" jsonschema:"Lower bound of the expected band"`
+	Upper     float64 `json:"upper" jsonschema:"Upper bound of the expected band"`
+	Score     float64 `json:"score" jsonschema:"Deviation in standard deviations (zscore) or IQRs (iqr)"`
+	Direction string  `json:"direction" jsonschema:"spike when above the band, drop when below"`
+}
+
+// AnomalyDetection describes how anomalies are detected in a daily series.
+type AnomalyDetection struct {
+	Method    AnomalyMethod
+	Window    int
+	Threshold float64
+}
+
+// Validate validates the method, window and threshold of the detection.
+func (d *AnomalyDetection) Validate() error {
+	if !d.Method.IsValid() {
+		return fmt.Errorf("invalid method '%s'. Valid methods: %v", d.Method, GetAllAnomalyMethods())
+	}
+	if d.Window < 2 {
+		return fmt.Errorf("window must be >= 2, got %d", d.Window)
+	}
+	if d.Threshold <= 0 {
+		return errors.New("threshold must be greater than 0")
+	}
+	return nil
+}
+
+// Detect compares each day against a band computed from the Window days before it and returns
+// the days outside the band in date order. The first Window days only serve as history, and days
+// whose window shows no variation at all are skipped because no band can be estimated for them.
+func (d *AnomalyDetection) Detect(series []SeriesPoint) []Anomaly {
+	anomalies := []Anomaly{}
+	for i := d.Window; i < len(series); i++ {
+		window := make([]float64, d.Window)
+		for j := range window {
+			window[j] = series[i-d.Window+j].Value
+		}
+
+		expected, spread, lower, upper := d.band(window)
+		if spread == 0 {
+			continue
+		}
+
+		actual := series[i].Value
+		if actual >= lower && actual <= upper {
+			continue
+		}
+
+		direction := "spike"
+		if actual < lower {
+			direction = "drop"
+		}
+		anomalies = append(anomalies, Anomaly{
+			Date:      series[i].Date,
+			Actual:    actual,
+			Expected:  roundTo2(expected),
+			Lower:     roundTo2(lower),
+			Upper:     roundTo2(upper),
+			Score:     roundTo2((actual - expected) / spread),
+			Direction: direction,
+		})
+	}
+	return anomalies
+}
+
+// band returns the expected value, the spread and the bounds of the band for a window.
+func (d *AnomalyDetection) band(window []float64) (float64, float64, float64, float64) {
+	if d.Method == AnomalyIQR {
+		sorted := slices.Sorted(slices.Values(window))
+		q1 := quantile(sorted, quartileLower)
+		q3 := quantile(sorted, quartileUpper)
+		iqr := q3 - q1
+		return quantile(sorted, quartileMedian), iqr, q1 - d.Threshold*iqr, q3 + d.Threshold*iqr
+	}
+
+	var sum float64
+	for _, value := range window {
+		sum += value
+	}
+	mean := sum / float64(len(window))
+
+	var squares float64
+	for _, value := range window {
+		squares += (value - mean) * (value - mean)
+	}
+	stddev := math.Sqrt(squares / float64(len(window)))
+	return mean, stddev, mean - d.Threshold*stddev, mean + d.Threshold*stddev
+}
+
+// quantile returns the q-th quantile of sorted values using linear interpolation.
+func quantile(sorted []float64, q float64) float64 {
+	position := q * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
+
+// roundTo2 rounds a value to two decimal places.
+func roundTo2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// AnomalyResponse represents the result of anomaly detection over a daily series.
+type AnomalyResponse struct {
+	ReportName string    `json:"report_name" jsonschema:"Name of the analyzed report"`
+	Metric     string    `json:"metric" jsonschema:"Metric the daily series was built from"`
+	Method     string    `json:"method" jsonschema:"Detection method: zscore or iqr"`
+	Window     int       `json:"window" jsonschema:"Number of preceding days each day is compared against"`
+	Threshold  float64   `json:"threshold" jsonschema:"Width of the expected band"`
+	Days       int       `json:"days" jsonschema:"Number of days in the series"`
+	Anomalies  []Anomaly `json:"anomalies" jsonschema:"Days outside the expected band, in date order"`
+	Records    int       `json:"records" jsonschema:"Number of report rows the series was built from"`
+	Truncated  bool      `json:"truncated,omitempty" jsonschema:"More data was available beyond max_records"`
+	Error      *Error    `json:"error,omitempty" jsonschema:"Error information if request failed"`
+}
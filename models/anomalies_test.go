@@ -0,0 +1,164 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+func TestDailySeries(t *testing.T) {
+	t.Parallel()
+
+	rows := []models.Reports{
+		{ID: 1, Date: "2024-01-03", Device: "mobile", Visits: 30},
+		{ID: 2, Date: "2024-01-01", Device: "mobile", Visits: 10},
+		{ID: 3, Date: "2024-01-01", Device: "desktop", Visits: 5},
+	}
+
+	series, err := models.DailySeries(rows, "visits")
+	if err != nil {
+		t.Fatalf("DailySeries() unexpected error: %v", err)
+	}
+
+	expected := []models.SeriesPoint{
+		{Date: "2024-01-01", Value: 15},
+		{Date: "2024-01-02", Value: 0},
+		{Date: "2024-01-03", Value: 30},
+	}
+	if len(series) != len(expected) {
+		t.Fatalf("DailySeries() = %+v, want %+v", series, expected)
+	}
+	for i := range expected {
+		if series[i] != expected[i] {
+			t.Errorf("DailySeries()[%d] = %+v, want %+v", i, series[i], expected[i])
+		}
+	}
+
+	if _, err = models.DailySeries([]models.Reports{{ID: 7, Date: "2024-01-01T10"}}, "visits"); err == nil ||
+		!strings.Contains(err.Error(), "invalid date") {
+		t.Errorf("DailySeries() error = %v, want invalid date error", err)
+	}
+	if _, err = models.DailySeries(rows, "device"); err == nil {
+		t.Error("DailySeries() expected error for non-metric field")
+	}
+}
+
+func TestAnomalyDetection_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		detection models.AnomalyDetection
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			name:      "valid zscore",
+			detection: models.AnomalyDetection{Method: models.AnomalyZScore, Window: 7, Threshold: 3},
+			expectErr: false,
+		},
+		{
+			name:      "invalid method",
+			detection: models.AnomalyDetection{Method: "prophet", Window: 7, Threshold: 3},
+			expectErr: true,
+			errMsg:    "invalid method 'prophet'",
+		},
+		{
+			name:      "window too small",
+			detection: models.AnomalyDetection{Method: models.AnomalyIQR, Window: 1, Threshold: 1.5},
+			expectErr: true,
+			errMsg:    "window must be >= 2",
+		},
+		{
+			name:      "non-positive threshold",
+			detection: models.AnomalyDetection{Method: models.AnomalyIQR, Window: 7, Threshold: -1},
+			expectErr: true,
+			errMsg:    "threshold must be greater than 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.detection.Validate()
+
+			validateTestResult(t, err, tt.expectErr, tt.errMsg)
+		})
+	}
+}
+
+func TestAnomalyDetection_Detect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		detection models.AnomalyDetection
+		values    []float64
+		expected  []models.Anomaly
+	}{
+		{
+			name:      "zscore spike",
+			detection: models.AnomalyDetection{Method: models.AnomalyZScore, Window: 3, Threshold: 3},
+			values:    []float64{100, 110, 90, 100, 300, 100},
+			expected: []models.Anomaly{
+				{Date: "2024-01-05", Actual: 300, Expected: 100, Lower: 75.51, Upper: 124.49, Score: 24.49,
+					Direction: "spike"},
+			},
+		},
+		{
+			name:      "iqr spike",
+			detection: models.AnomalyDetection{Method: models.AnomalyIQR, Window: 4, Threshold: 1.5},
+			values:    []float64{10, 12, 11, 13, 50, 12, 0},
+			expected: []models.Anomaly{
+				{Date: "2024-01-05", Actual: 50, Expected: 11.5, Lower: 8.5, Upper: 14.5, Score: 25.67,
+					Direction: "spike"},
+			},
+		},
+		{
+			name:      "zscore drop",
+			detection: models.AnomalyDetection{Method: models.AnomalyZScore, Window: 3, Threshold: 2},
+			values:    []float64{100, 110, 90, 10},
+			expected: []models.Anomaly{
+				{Date: "2024-01-04", Actual: 10, Expected: 100, Lower: 83.67, Upper: 116.33, Score: -11.02,
+					Direction: "drop"},
+			},
+		},
+		{
+			name:      "flat window is skipped",
+			detection: models.AnomalyDetection{Method: models.AnomalyZScore, Window: 3, Threshold: 3},
+			values:    []float64{5, 5, 5, 9},
+			expected:  []models.Anomaly{},
+		},
+		{
+			name:      "series shorter than window",
+			detection: models.AnomalyDetection{Method: models.AnomalyIQR, Window: 7, Threshold: 1.5},
+			values:    []float64{1, 2, 100},
+			expected:  []models.Anomaly{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			series := make([]models.SeriesPoint, len(tt.values))
+			for i, value := range tt.values {
+				date := time.Date(2024, time.January, 1+i, 0, 0, 0, 0, time.UTC)
+				series[i] = models.SeriesPoint{Date: date.Format(models.DateLayout), Value: value}
+			}
+
+			anomalies := tt.detection.Detect(series)
+			if len(anomalies) != len(tt.expected) {
+				t.Fatalf("Detect() = %+v, want %+v", anomalies, tt.expected)
+			}
+			for i := range tt.expected {
+				if anomalies[i] != tt.expected[i] {
+					t.Errorf("Detect()[%d] = %+v, want %+v", i, anomalies[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
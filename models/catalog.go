@@ -0,0 +1,139 @@
+package models
+
+import "slices"
+
+// reportFilters are the request filters supported by every report.
+var reportFilters = []string{"after", "before", "agency_name", "domain"}
+
+// ReportInfo describes a report type: what it contains and how it can be queried.
+type ReportInfo struct {
+	Name             ReportType `json:"name" jsonschema:"Report name, as passed to report_name"`
+	Description      string     `json:"description" jsonschema:"What the report contains"`
+	DefaultDimension string     `json:"default_dimension,omitempty" jsonschema:"Field identifying a row within a day"`
+	Dimensions       []string   `json:"dimensions" jsonschema:"Categorical fields of the report rows"`
+	Metrics          []string   `json:"metrics" jsonschema:"Numeric fields of the report rows"`
+	Filters          []string   `json:"filters" jsonschema:"Request filters the report supports"`
+}
+
+// reportCatalog is the registry of all report types, in the order they are presented to clients.
+// Report validation, the report resources and the get_report tool description are derived from it.
+var reportCatalog = []ReportInfo{
+	{
+		Name:             ReportTypeDevices,
+		Description:      "Device types used by visitors (desktop, mobile, tablet)",
+		DefaultDimension: "device",
+		Dimensions:       []string{"date", "device"},
+		Metrics:          []string{"visits"},
+		Filters:          reportFilters,
+	},
+	{
+		Name:             ReportTypeBrowsers,
+		Description:      "Browser usage statistics (Chrome, Safari, Firefox, etc.)",
+		DefaultDimension: "browser",
+		Dimensions:       []string{"date", "browser"},
+		Metrics:          []string{"visits"},
+		Filters:          reportFilters,
+	},
+	{
+		Name:             ReportTypeOperatingSystems,
+		Description:      "Operating system statistics (Windows, macOS, iOS, etc.)",
+		DefaultDimension: "os",
+		Dimensions:       []string{"date", "os"},
+		Metrics:          []string{"visits"},
+		Filters:          reportFilters,
+	},
+	{
+		Name:             ReportTypeLanguages,
+		Description:      "Language preferences of visitors",
+		DefaultDimension: "language",
+		Dimensions:       []string{"date", "language", "language_code"},
+		Metrics:          []string{"visits"},
+		Filters:          reportFilters,
+	},
+	{
+		Name:             ReportTypeCountries,
+		Description:      "Geographic breakdown by country",
+		DefaultDimension: "country",
+		Dimensions:       []string{"date", "country"},
+		Metrics:          []string{"visits"},
+		Filters:          reportFilters,
+	},
+	{
+		Name:             ReportTypeCities,
+		Description:      "Geographic breakdown by city",
+		DefaultDimension: "city",
+		Dimensions:       []string{"date", "city"},
+		Metrics:          []string{"visits"},
+		Filters:          reportFilters,
+	},
+	{
+		Name:        ReportTypeTraffic,
+		Description: "Traffic volume and trends over time",
+		Dimensions:  []string{"date"},
+		Metrics:     []string{"visits", "users", "pageviews", "avg_session_duration", "bounce_rate"},
+		Filters:     reportFilters,
+	},
+	{
+		Name:             ReportTypeTopPages,
+		Description:      "Most visited pages and their metrics",
+		DefaultDimension: "page",
+		Dimensions:       []string{"date", "page", "page_title", "landing_page"},
+		Metrics:          []string{"visits", "pageviews"},
+		Filters:          reportFilters,
+	},
+	{
+		Name:             ReportTypeDownloads,
+		Description:      "File download statistics and popular downloads",
+		DefaultDimension: "file_name",
+		Dimensions:       []string{"date", "file_name", "page", "page_title", "event_label"},
+		Metrics:          []string{"total_events"},
+		Filters:          reportFilters,
+	},
+	{
+		Name:        ReportTypeActiveUsers,
+		Description: "Real-time active user statistics",
+		Dimensions:  []string{"date", "hour"},
+		Metrics:     []string{"active_visitors"},
+		Filters:     reportFilters,
+	},
+	{
+		Name:             ReportTypeSources,
+		Description:      "Traffic source analysis (direct, referral, search, etc.)",
+		DefaultDimension: "source",
+		Dimensions:       []string{"date", "source", "session_default_channel_group"},
+		Metrics:          []string{"visits"},
+		Filters:          reportFilters,
+	},
+	{
+		Name:             ReportTypeDomains,
+		Description:      "Analytics by domain for multi-domain agencies",
+		DefaultDimension: "domain",
+		Dimensions:       []string{"date", "domain"},
+		Metrics:          []string{"visits"},
+		Filters:          reportFilters,
+	},
+	{
+		Name:        ReportTypeAgencies,
+		Description: "Analytics aggregated by government agency",
+		Dimensions:  []string{"date", "report_agency"},
+		Metrics:     []string{"visits"},
+		Filters:     []string{"after", "before"},
+	},
+}
+
+// ReportCatalog returns the descriptions of all report types. The field lists of the entries
+// are shared with the registry and must not be modified.
+func ReportCatalog() []ReportInfo {
+	return slices.Clone(reportCatalog)
+}
+
+// LookupReport returns the description of the report type with the given name.
+func LookupReport(name string) (ReportInfo, bool) {
+	i := slices.IndexFunc(reportCatalog, func(info ReportInfo) bool {
+		return string(info.Name) == name
+	})
+	if i < 0 {
+		return ReportInfo{}, false
+	}
+	return reportCatalog[i], true
+}
@@ -0,0 +1,50 @@
+package models_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+func TestReportCatalog(t *testing.T) {
+	t.Parallel()
+
+	for _, report := range models.ReportCatalog() {
+		t.Run(report.Name.String(), func(t *testing.T) {
+			t.Parallel()
+
+			if report.Description == "" {
+				t.Error("Report should have a description")
+			}
+			for _, field := range report.Dimensions {
+				if !models.IsDimensionField(field) {
+					t.Errorf("Dimension %q is not a dimension field", field)
+				}
+			}
+			for _, field := range report.Metrics {
+				if !models.IsMetricField(field) {
+					t.Errorf("Metric %q is not a metric field", field)
+				}
+			}
+			if report.DefaultDimension != "" && !slices.Contains(report.Dimensions, report.DefaultDimension) {
+				t.Errorf("Default dimension %q is not one of %v", report.DefaultDimension, report.Dimensions)
+			}
+			if report.Name.DefaultDimension() != report.DefaultDimension {
+				t.Errorf("DefaultDimension() = %q, want %q", report.Name.DefaultDimension(), report.DefaultDimension)
+			}
+		})
+	}
+}
+
+func TestLookupReport(t *testing.T) {
+	t.Parallel()
+
+	info, ok := models.LookupReport("top-pages")
+	if !ok || info.Name != models.ReportTypeTopPages || info.DefaultDimension != "page" {
+		t.Errorf("LookupReport(top-pages) = %+v, %v", info, ok)
+	}
+	if _, ok = models.LookupReport("weather"); ok {
+		t.Error("LookupReport(weather) should not find a report")
+	}
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Period is a concrete, inclusive date range.
+type Period struct {
+	After     string `json:"after" jsonschema:"First date of the period (YYYY-MM-DD)"`
+	Before    string `json:"before" jsonschema:"Last date of the period (YYYY-MM-DD)"`
+	Truncated bool   `json:"truncated" jsonschema:"Whether more records of the period were available beyond max_records"`
+}
+
+// MetricDelta is the change of a metric between two periods.
+type MetricDelta struct {
+	Previous      float64  `json:"previous" jsonschema:"Total in the previous period"`
+	Current       float64  `json:"current" jsonschema:"Total in the current period"`
+	Change        float64  `json:"change" jsonschema:"Absolute change from previous to current"`
+	PercentChange *float64 `json:"percent_change,omitempty" jsonschema:"Change in percent, omitted when previous is 0"`
+}
+
+// ComparisonRow holds the metric changes of one dimension value.
+type ComparisonRow struct {
+	Key     string                 `json:"key" jsonschema:"Value of the dimension field"`
+	Metrics map[string]MetricDelta `json:"metrics" jsonschema:"Changes keyed by metric name"`
+}
+
+// ComparisonResponse represents the response of a period-over-period comparison.
+type ComparisonResponse struct {
+	ReportName     string          `json:"report_name" jsonschema:"Name of the compared report"`
+	Dimension      string          `json:"dimension,omitempty" jsonschema:"Field the rows were joined on"`
+	CurrentPeriod  Period          `json:"current_period" jsonschema:"The current period"`
+	PreviousPeriod Period          `json:"previous_period" jsonschema:"The period compared against"`
+	Metrics        []string        `json:"metrics" jsonschema:"Compared metric fields"`
+	Totals         ComparisonRow   `json:"totals" jsonschema:"Changes across all rows"`
+	Rows           []ComparisonRow `json:"rows" jsonschema:"Changes per dimension value, largest first"`
+	Error          *Error          `json:"error,omitempty" jsonschema:"Error information if request failed"`
+}
+
+// NewMetricDelta computes the change between a previous and a current value.
+func NewMetricDelta(previous, current float64) MetricDelta {
+	delta := MetricDelta{Previous: previous, Current: current, Change: current - previous}
+	if previous != 0 {
+		percent := roundTo2((current - previous) / math.Abs(previous) * 100)
+		delta.PercentChange = &percent
+	}
+	return delta
+}
+
+// ComparePeriods sums the metrics of both periods per dimension value and joins them.
+// Values present in only one period are compared against zero. Rows are ordered by the
+// absolute change of the first metric, largest first.
+func ComparePeriods(previous, current []Reports, dimension string, metrics []string) []ComparisonRow {
+	previousSums := sumByDimension(previous, dimension, metrics)
+	currentSums := sumByDimension(current, dimension, metrics)
+
+	keys := make(map[string]bool)
+	for key := range previousSums {
+		keys[key] = true
+	}
+	for key := range currentSums {
+		keys[key] = true
+	}
+
+	rows := make([]ComparisonRow, 0, len(keys))
+	for key := range keys {
+		row := ComparisonRow{Key: key, Metrics: make(map[string]MetricDelta, len(metrics))}
+		for _, metric := range metrics {
+			row.Metrics[metric] = NewMetricDelta(previousSums[key][metric], currentSums[key][metric])
+		}
+		rows = append(rows, row)
+	}
+
+	slices.SortFunc(rows, func(x, y ComparisonRow) int {
+		if len(metrics) > 0 {
+			xChange := math.Abs(x.Metrics[metrics[0]].Change)
+			yChange := math.Abs(y.Metrics[metrics[0]].Change)
+			if c := cmp.Compare(yChange, xChange); c != 0 {
+				return c
+			}
+		}
+		return strings.Compare(x.Key, y.Key)
+	})
+
+	return rows
+}
+
+// TotalComparison sums the comparison rows into a single row.
+func TotalComparison(rows []ComparisonRow, metrics []string) ComparisonRow {
+	totals := ComparisonRow{Key: "total", Metrics: make(map[string]MetricDelta, len(metrics))}
+	for _, metric := range metrics {
+		var previous, current float64
+		for _, row := range rows {
+			previous += row.Metrics[metric].Previous
+			current += row.Metrics[metric].Current
+		}
+		totals.Metrics[metric] = NewMetricDelta(previous, current)
+	}
+	return totals
+}
+
+// sumByDimension sums the metrics of the rows per value of the dimension field.
+// With an empty dimension all rows are summed into a single group.
+func sumByDimension(rows []Reports, dimension string, metrics []string) map[string]map[string]float64 {
+	sums := make(map[string]map[string]float64)
+	for i := range rows {
+		key, _ := rows[i].Dimension(dimension)
+		if sums[key] == nil {
+			sums[key] = make(map[string]float64, len(metrics))
+		}
+		for _, metric := range metrics {
+			value, _ := rows[i].Metric(metric)
+			sums[key][metric] += value
+		}
+	}
+	return sums
+}
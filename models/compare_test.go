@@ -0,0 +1,87 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+func TestNewMetricDelta(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		previous        float64
+		current         float64
+		expectedChange  float64
+		expectedPercent *float64
+	}{
+		{name: "increase", previous: 200, current: 250, expectedChange: 50, expectedPercent: ptr(25.0)},
+		{name: "decrease", previous: 300, current: 100, expectedChange: -200, expectedPercent: ptr(-66.67)},
+		{name: "unchanged", previous: 10, current: 10, expectedChange: 0, expectedPercent: ptr(0.0)},
+		{name: "from zero", previous: 0, current: 40, expectedChange: 40, expectedPercent: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			delta := models.NewMetricDelta(tt.previous, tt.current)
+			if delta.Change != tt.expectedChange {
+				t.Errorf("Change = %v, want %v", delta.Change, tt.expectedChange)
+			}
+			switch {
+			case tt.expectedPercent == nil && delta.PercentChange != nil:
+				t.Errorf("PercentChange = %v, want nil", *delta.PercentChange)
+			case tt.expectedPercent != nil && (delta.PercentChange == nil || *delta.PercentChange != *tt.expectedPercent):
+				t.Errorf("PercentChange = %v, want %v", delta.PercentChange, *tt.expectedPercent)
+			}
+		})
+	}
+}
+
+func TestComparePeriods(t *testing.T) {
+	t.Parallel()
+
+	previous := []models.Reports{
+		{Browser: "Chrome", Visits: 500, Users: 400},
+		{Browser: "Firefox", Visits: 100, Users: 80},
+		{Browser: "Chrome", Visits: 100, Users: 50},
+	}
+	current := []models.Reports{
+		{Browser: "Chrome", Visits: 550, Users: 450},
+		{Browser: "Safari", Visits: 300, Users: 250},
+	}
+
+	rows := models.ComparePeriods(previous, current, "browser", []string{"visits", "users"})
+
+	expectedKeys := []string{"Safari", "Firefox", "Chrome"}
+	if len(rows) != len(expectedKeys) {
+		t.Fatalf("ComparePeriods() returned %d rows, want %d", len(rows), len(expectedKeys))
+	}
+	for i, key := range expectedKeys {
+		if rows[i].Key != key {
+			t.Errorf("Row %d key = %q, want %q", i, rows[i].Key, key)
+		}
+	}
+
+	chrome := rows[2].Metrics["visits"]
+	if chrome.Previous != 600 || chrome.Current != 550 || chrome.Change != -50 {
+		t.Errorf("Chrome visits = %+v, want 600 -> 550", chrome)
+	}
+	if firefox := rows[1].Metrics["users"]; firefox.Current != 0 || firefox.Change != -80 {
+		t.Errorf("Firefox users = %+v, want 80 -> 0", firefox)
+	}
+
+	totals := models.TotalComparison(rows, []string{"visits", "users"})
+	if visits := totals.Metrics["visits"]; visits.Previous != 700 || visits.Current != 850 {
+		t.Errorf("Total visits = %+v, want 700 -> 850", visits)
+	}
+	if totals.Key != "total" {
+		t.Errorf("Totals key = %q, want total", totals.Key)
+	}
+}
+
+func ptr(value float64) *float64 {
+	return &value
+}
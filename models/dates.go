@@ -0,0 +1,173 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DateLayout is the date format used by the DAP API.
+	DateLayout = time.DateOnly
+
+	daysPerWeek      = 7
+	hoursPerDay      = 24
+	monthsPerQuarter = 3
+	monthsPerYear    = 12
+)
+
+var (
+	lastNPattern    = regexp.MustCompile(`^(?:last|past) (\d+) (day|week|month)s?$`)
+	quarterPattern  = regexp.MustCompile(`^(\d{4})-q([1-4])$`)
+	monthPattern    = regexp.MustCompile(`^(\d{4})-(\d{2})$`)
+	yearPattern     = regexp.MustCompile(`^\d{4}$`)
+	rangeSeparators = []string{"..", " to "}
+)
+
+// DateRange is an inclusive range of calendar dates.
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// After returns the start of the range in the API date format.
+func (r DateRange) After() string {
+	return r.Start.Format(DateLayout)
+}
+
+// Before returns the end of the range in the API date format.
+func (r DateRange) Before() string {
+	return r.End.Format(DateLayout)
+}
+
+// ParseDateExpression resolves a date expression to a concrete date range relative to now.
+//
+// Supported expressions are:
+//   - Dates: "2024-03-15", "today", "yesterday"
+//   - Periods: "2024", "2024-03", "2024-Q1", "this month", "last month", "this year", "last year"
+//   - Relative ranges of complete days before today: "last 7 days", "past 2 weeks", "last 3 months"
+//   - Explicit ranges of any of the above: "2024-01..2024-03", "2024-01-01 to yesterday"
+func ParseDateExpression(expr string, now time.Time) (DateRange, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(expr)), " ")
+	if normalized == "" {
+		return DateRange{}, errors.New("empty date expression")
+	}
+
+	for _, separator := range rangeSeparators {
+		if from, to, found := strings.Cut(normalized, separator); found {
+			start, err := parseSingleExpression(strings.TrimSpace(from), now)
+			if err != nil {
+				return DateRange{}, err
+			}
+			end, err := parseSingleExpression(strings.TrimSpace(to), now)
+			if err != nil {
+				return DateRange{}, err
+			}
+			if end.End.Before(start.Start) {
+				return DateRange{}, fmt.Errorf("date range '%s' ends before it starts", expr)
+			}
+			return DateRange{Start: start.Start, End: end.End}, nil
+		}
+	}
+
+	return parseSingleExpression(normalized, now)
+}
+
+// parseSingleExpression resolves an expression that does not contain a range separator.
+func parseSingleExpression(expr string, now time.Time) (DateRange, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if date, err := time.Parse(DateLayout, expr); err == nil {
+		return DateRange{Start: date, End: date}, nil
+	}
+
+	switch expr {
+	case "today":
+		return DateRange{Start: today, End: today}, nil
+	case "yesterday":
+		yesterday := today.AddDate(0, 0, -1)
+		return DateRange{Start: yesterday, End: yesterday}, nil
+	case "this month":
+		return DateRange{Start: startOfMonth(today), End: today}, nil
+	case "last month":
+		return monthRange(startOfMonth(today).AddDate(0, -1, 0), 1), nil
+	case "this year":
+		return DateRange{Start: time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.UTC), End: today}, nil
+	case "last year":
+		return yearRange(today.Year() - 1), nil
+	}
+
+	if m := lastNPattern.FindStringSubmatch(expr); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n < 1 {
+			return DateRange{}, fmt.Errorf("invalid number in date expression '%s'", expr)
+		}
+		yesterday := today.AddDate(0, 0, -1)
+		switch m[2] {
+		case "day":
+			return DateRange{Start: today.AddDate(0, 0, -n), End: yesterday}, nil
+		case "week":
+			return DateRange{Start: today.AddDate(0, 0, -daysPerWeek*n), End: yesterday}, nil
+		default:
+			// Complete calendar months before the current one
+			return monthRange(startOfMonth(today).AddDate(0, -n, 0), n), nil
+		}
+	}
+
+	if m := quarterPattern.FindStringSubmatch(expr); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		quarter, _ := strconv.Atoi(m[2])
+		firstMonth := time.Month(monthsPerQuarter*(quarter-1) + 1)
+		return monthRange(time.Date(year, firstMonth, 1, 0, 0, 0, 0, time.UTC), monthsPerQuarter), nil
+	}
+
+	if m := monthPattern.FindStringSubmatch(expr); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		if month < int(time.January) || month > int(time.December) {
+			return DateRange{}, fmt.Errorf("invalid month in date expression '%s'", expr)
+		}
+		return monthRange(time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC), 1), nil
+	}
+
+	if yearPattern.MatchString(expr) {
+		year, _ := strconv.Atoi(expr)
+		return yearRange(year), nil
+	}
+
+	return DateRange{}, fmt.Errorf("unrecognized date expression '%s', expected YYYY-MM-DD, YYYY-MM, "+
+		"YYYY-Qn, YYYY, today, yesterday, this/last month, this/last year or last N days/weeks/months", expr)
+}
+
+// startOfMonth returns the first day of the month containing date.
+func startOfMonth(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// monthRange returns the range covering n calendar months starting at the first day of start's month.
+func monthRange(start time.Time, n int) DateRange {
+	return DateRange{Start: start, End: start.AddDate(0, n, -1)}
+}
+
+// yearRange returns the range covering the given calendar year.
+func yearRange(year int) DateRange {
+	return DateRange{
+		Start: time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(year+1, 1, 0, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// Previous returns the period of equal length immediately before the range. Ranges made of
+// whole calendar months move back by the same number of months, others by the same number of days.
+func (r DateRange) Previous() DateRange {
+	if r.Start.Day() == 1 && r.End.AddDate(0, 0, 1).Day() == 1 {
+		months := (r.End.Year()-r.Start.Year())*monthsPerYear + int(r.End.Month()-r.Start.Month()) + 1
+		return monthRange(r.Start.AddDate(0, -months, 0), months)
+	}
+
+	days := int(r.End.Sub(r.Start).Hours()/hoursPerDay) + 1
+	return DateRange{Start: r.Start.AddDate(0, 0, -days), End: r.Start.AddDate(0, 0, -1)}
+}
@@ -0,0 +1,138 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+// referenceNow is the fixed clock used to resolve relative date expressions in tests.
+var referenceNow = time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC)
+
+func TestParseDateExpression(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		expr           string
+		expectedAfter  string
+		expectedBefore string
+	}{
+		{name: "single date", expr: "2024-01-15", expectedAfter: "2024-01-15", expectedBefore: "2024-01-15"},
+		{name: "today", expr: "today", expectedAfter: "2024-03-15", expectedBefore: "2024-03-15"},
+		{name: "yesterday", expr: "Yesterday", expectedAfter: "2024-03-14", expectedBefore: "2024-03-14"},
+		{name: "last 7 days", expr: "last 7 days", expectedAfter: "2024-03-08", expectedBefore: "2024-03-14"},
+		{name: "past 1 day", expr: "past 1 day", expectedAfter: "2024-03-14", expectedBefore: "2024-03-14"},
+		{name: "last 2 weeks", expr: "last  2 weeks", expectedAfter: "2024-03-01", expectedBefore: "2024-03-14"},
+		{name: "last 3 months", expr: "last 3 months", expectedAfter: "2023-12-01", expectedBefore: "2024-02-29"},
+		{name: "this month", expr: "this month", expectedAfter: "2024-03-01", expectedBefore: "2024-03-15"},
+		{name: "last month", expr: "last month", expectedAfter: "2024-02-01", expectedBefore: "2024-02-29"},
+		{name: "this year", expr: "this year", expectedAfter: "2024-01-01", expectedBefore: "2024-03-15"},
+		{name: "last year", expr: "last year", expectedAfter: "2023-01-01", expectedBefore: "2023-12-31"},
+		{name: "quarter", expr: "2024-Q1", expectedAfter: "2024-01-01", expectedBefore: "2024-03-31"},
+		{name: "fourth quarter", expr: "2023-q4", expectedAfter: "2023-10-01", expectedBefore: "2023-12-31"},
+		{name: "month", expr: "2024-03", expectedAfter: "2024-03-01", expectedBefore: "2024-03-31"},
+		{name: "year", expr: "2023", expectedAfter: "2023-01-01", expectedBefore: "2023-12-31"},
+		{name: "range of months", expr: "2024-01..2024-02", expectedAfter: "2024-01-01", expectedBefore: "2024-02-29"},
+		{name: "range to yesterday", expr: "2024-03-01 to yesterday", expectedAfter: "2024-03-01",
+			expectedBefore: "2024-03-14"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dates, err := models.ParseDateExpression(tt.expr, referenceNow)
+			if err != nil {
+				t.Fatalf("ParseDateExpression(%q) unexpected error: %v", tt.expr, err)
+			}
+			if dates.After() != tt.expectedAfter || dates.Before() != tt.expectedBefore {
+				t.Errorf("ParseDateExpression(%q) = %s..%s, want %s..%s",
+					tt.expr, dates.After(), dates.Before(), tt.expectedAfter, tt.expectedBefore)
+			}
+		})
+	}
+}
+
+func TestParseDateExpression_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		expr   string
+		errMsg string
+	}{
+		{name: "empty", expr: "  ", errMsg: "empty date expression"},
+		{name: "unknown expression", expr: "next week", errMsg: "unrecognized date expression"},
+		{name: "invalid month", expr: "2024-13", errMsg: "invalid month"},
+		{name: "invalid quarter", expr: "2024-Q5", errMsg: "unrecognized date expression"},
+		{name: "zero days", expr: "last 0 days", errMsg: "invalid number"},
+		{name: "invalid calendar date", expr: "2024-02-30", errMsg: "unrecognized date expression"},
+		{name: "reversed range", expr: "2024-03..2024-01", errMsg: "ends before it starts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := models.ParseDateExpression(tt.expr, referenceNow)
+			if err == nil {
+				t.Fatalf("ParseDateExpression(%q) expected error but got none", tt.expr)
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("ParseDateExpression(%q) error = %v, want error containing %q", tt.expr, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestReportParams_ResolveDates(t *testing.T) {
+	t.Parallel()
+
+	params := models.ReportParams{After: "2024-Q1", Before: "last month"}
+	if err := params.ResolveDates(referenceNow); err != nil {
+		t.Fatalf("ResolveDates() unexpected error: %v", err)
+	}
+	if params.After != "2024-01-01" || params.Before != "2024-02-29" {
+		t.Errorf("ResolveDates() = %s..%s, want 2024-01-01..2024-02-29", params.After, params.Before)
+	}
+
+	params = models.ReportParams{After: "sometime"}
+	if err := params.ResolveDates(referenceNow); err == nil || !strings.Contains(err.Error(), "invalid after") {
+		t.Errorf("ResolveDates() error = %v, want invalid after error", err)
+	}
+}
+
+func TestDateRange_Previous(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr           string
+		expectedAfter  string
+		expectedBefore string
+	}{
+		{expr: "last month", expectedAfter: "2024-01-01", expectedBefore: "2024-01-31"},
+		{expr: "2024-Q1", expectedAfter: "2023-10-01", expectedBefore: "2023-12-31"},
+		{expr: "2024-03", expectedAfter: "2024-02-01", expectedBefore: "2024-02-29"},
+		{expr: "last 7 days", expectedAfter: "2024-03-01", expectedBefore: "2024-03-07"},
+		{expr: "2024-01-10..2024-01-12", expectedAfter: "2024-01-07", expectedBefore: "2024-01-09"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			dates, err := models.ParseDateExpression(tt.expr, referenceNow)
+			if err != nil {
+				t.Fatalf("ParseDateExpression(%q) unexpected error: %v", tt.expr, err)
+			}
+			previous := dates.Previous()
+			if previous.After() != tt.expectedAfter || previous.Before() != tt.expectedBefore {
+				t.Errorf("Previous() = %s..%s, want %s..%s",
+					previous.After(), previous.Before(), tt.expectedAfter, tt.expectedBefore)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// reportFieldIndex maps the JSON names of all report fields to their struct field index.
+var reportFieldIndex = buildReportFieldIndex()
+
+// buildReportFieldIndex reads the JSON tag names of the Reports struct.
+func buildReportFieldIndex() map[string]int {
+	index := make(map[string]int)
+	reportType := reflect.TypeFor[Reports]()
+	for i := range reportType.NumField() {
+		name, _, _ := strings.Cut(reportType.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			index[name] = i
+		}
+	}
+	return index
+}
+
+// dimensionFields maps the JSON names of categorical report fields to their accessors.
+var dimensionFields = map[string]func(*Reports) string{
+	"report_name":                   func(r *Reports) string { return r.ReportName },
+	"report_agency":                 func(r *Reports) string { return r.ReportAgency },
+	"date":                          func(r *Reports) string { return r.Date },
+	"browser":                       func(r *Reports) string { return r.Browser },
+	"city":                          func(r *Reports) string { return r.City },
+	"country":                       func(r *Reports) string { return r.Country },
+	"device":                        func(r *Reports) string { return r.Device },
+	"domain":                        func(r *Reports) string { return r.Domain },
+	"event_label":                   func(r *Reports) string { return r.EventLabel },
+	"file_name":                     func(r *Reports) string { return r.FileName },
+	"hour":                          func(r *Reports) string { return r.Hour },
+	"landing_page":                  func(r *Reports) string { return r.LandingPage },
+	"language":                      func(r *Reports) string { return r.Language },
+	"language_code":                 func(r *Reports) string { return r.LanguageCode },
+	"mobile_device":                 func(r *Reports) string { return r.MobileDevice },
+	"os":                            func(r *Reports) string { return r.OS },
+	"os_version":                    func(r *Reports) string { return r.OSVersion },
+	"page":                          func(r *Reports) string { return r.Page },
+	"page_title":                    func(r *Reports) string { return r.PageTitle },
+	"screen_resolution":             func(r *Reports) string { return r.ScreenResolution },
+	"session_default_channel_group": func(r *Reports) string { return r.SessionDefaultChannelGroup },
+	"source":                        func(r *Reports) string { return r.Source },
+}
+
+// metricFields maps the JSON names of numeric report fields to their accessors.
+var metricFields = map[string]func(*Reports) float64{
+	"active_visitors":       func(r *Reports) float64 { return float64(r.ActiveVisitors) },
+	"avg_session_duration":  func(r *Reports) float64 { return r.AvgSessionDuration },
+	"bounce_rate":           func(r *Reports) float64 { return r.BounceRate },
+	"pageviews":             func(r *Reports) float64 { return float64(r.Pageviews) },
+	"pageviews_per_session": func(r *Reports) float64 { return float64(r.PageviewsPerSession) },
+	"total_events":          func(r *Reports) float64 { return float64(r.TotalEvents) },
+	"users":                 func(r *Reports) float64 { return float64(r.Users) },
+	"visits":                func(r *Reports) float64 { return float64(r.Visits) },
+}
+
+// Dimension returns the value of the categorical field with the given JSON name.
+func (r *Reports) Dimension(name string) (string, bool) {
+	accessor, ok := dimensionFields[name]
+	if !ok {
+		return "", false
+	}
+	return accessor(r), true
+}
+
+// Metric returns the value of the numeric field with the given JSON name.
+func (r *Reports) Metric(name string) (float64, bool) {
+	accessor, ok := metricFields[name]
+	if !ok {
+		return 0, false
+	}
+	return accessor(r), true
+}
+
+// IsDimensionField checks if name is the JSON name of a categorical report field.
+func IsDimensionField(name string) bool {
+	_, ok := dimensionFields[name]
+	return ok
+}
+
+// IsMetricField checks if name is the JSON name of a numeric report field.
+func IsMetricField(name string) bool {
+	_, ok := metricFields[name]
+	return ok
+}
+
+// DimensionFieldNames returns the JSON names of all categorical report fields in sorted order.
+func DimensionFieldNames() []string {
+	return slices.Sorted(maps.Keys(dimensionFields))
+}
+
+// MetricFieldNames returns the JSON names of all numeric report fields in sorted order.
+func MetricFieldNames() []string {
+	return slices.Sorted(maps.Keys(metricFields))
+}
+
+// ReportFieldNames returns the JSON names of all report fields in sorted order.
+func ReportFieldNames() []string {
+	return slices.Sorted(maps.Keys(reportFieldIndex))
+}
+
+// ValidateReportFields checks that every name is the JSON name of a report field.
+func ValidateReportFields(fields []string) error {
+	for _, field := range fields {
+		if _, ok := reportFieldIndex[field]; !ok {
+			return fmt.Errorf("invalid field '%s'. Valid fields: %s", field, strings.Join(ReportFieldNames(), ", "))
+		}
+	}
+	return nil
+}
+
+// Values returns the values of the given JSON fields of the row, keyed by name, including zero
+// values. Unknown fields are ignored.
+func (r *Reports) Values(fields []string) map[string]any {
+	value := reflect.ValueOf(r).Elem()
+	values := make(map[string]any, len(fields))
+	for _, field := range fields {
+		if i, ok := reportFieldIndex[field]; ok {
+			values[field] = value.Field(i).Interface()
+		}
+	}
+	return values
+}
+
+// Select returns a copy of the row that keeps only the given JSON fields, plus the id,
+// report_name, report_agency and date fields that every row must carry. It is the projection
+// applied to report rows, so that their text and structured output hold the same fields.
+func (r *Reports) Select(fields []string) Reports {
+	projected := Reports{ID: r.ID, ReportName: r.ReportName, ReportAgency: r.ReportAgency, Date: r.Date}
+	source := reflect.ValueOf(r).Elem()
+	target := reflect.ValueOf(&projected).Elem()
+	for _, field := range fields {
+		if i, ok := reportFieldIndex[field]; ok {
+			target.Field(i).Set(source.Field(i))
+		}
+	}
+	return projected
+}
+
+// PresentFields returns the JSON names of the fields set in at least one row, in the order they
+// are declared in Reports. The id, report_name, report_agency and date fields are always included.
+func PresentFields(rows []Reports) []string {
+	reportType := reflect.TypeFor[Reports]()
+	present := make([]bool, reportType.NumField())
+	for i := range rows {
+		value := reflect.ValueOf(&rows[i]).Elem()
+		for j := range present {
+			present[j] = present[j] || !value.Field(j).IsZero()
+		}
+	}
+
+	var fields []string
+	for name, i := range reportFieldIndex {
+		if present[i] || requiredReportField(name) {
+			fields = append(fields, name)
+		}
+	}
+	slices.SortFunc(fields, func(a, b string) int {
+		return reportFieldIndex[a] - reportFieldIndex[b]
+	})
+	return fields
+}
+
+// requiredReportField reports whether the field is present in every report row.
+func requiredReportField(name string) bool {
+	switch name {
+	case "id", "report_name", "report_agency", "date":
+		return true
+	default:
+		return false
+	}
+}
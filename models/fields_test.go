@@ -0,0 +1,79 @@
+package models_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+func TestReports_FieldAccessors(t *testing.T) {
+	t.Parallel()
+
+	row := models.Reports{Device: "mobile", Date: "2024-01-01", Visits: 42, AvgSessionDuration: 1.5}
+
+	if value, ok := row.Dimension("device"); !ok || value != "mobile" {
+		t.Errorf("Dimension(device) = %q, %v, want mobile, true", value, ok)
+	}
+	if value, ok := row.Metric("visits"); !ok || value != 42 {
+		t.Errorf("Metric(visits) = %v, %v, want 42, true", value, ok)
+	}
+	if value, ok := row.Metric("avg_session_duration"); !ok || value != 1.5 {
+		t.Errorf("Metric(avg_session_duration) = %v, %v, want 1.5, true", value, ok)
+	}
+	if _, ok := row.Dimension("visits"); ok {
+		t.Error("Dimension(visits) should not be a dimension field")
+	}
+	if _, ok := row.Metric("unknown"); ok {
+		t.Error("Metric(unknown) should not be a metric field")
+	}
+}
+
+func TestReports_Projection(t *testing.T) {
+	t.Parallel()
+
+	row := models.Reports{
+		ID: 7, ReportName: "devices", ReportAgency: "gsa", Date: "2024-01-01",
+		Device: "mobile", Browser: "Safari", Visits: 42,
+	}
+	fields := []string{"date", "device", "visits"}
+
+	values := row.Values(fields)
+	expected := map[string]any{"date": "2024-01-01", "device": "mobile", "visits": 42}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Values() = %v, want %v", values, expected)
+	}
+
+	selected := row.Select(fields)
+	if selected.Device != "mobile" || selected.Visits != 42 || selected.Browser != "" {
+		t.Errorf("Select() = %+v, want device and visits without browser", selected)
+	}
+	if selected.ID != 7 || selected.ReportName != "devices" {
+		t.Errorf("Select() = %+v, should keep the required fields", selected)
+	}
+}
+
+func TestValidateReportFields(t *testing.T) {
+	t.Parallel()
+
+	if err := models.ValidateReportFields([]string{"id", "date", "visits", "bounce_rate"}); err != nil {
+		t.Errorf("ValidateReportFields() unexpected error: %v", err)
+	}
+
+	err := models.ValidateReportFields([]string{"visits", "Visits"})
+	validateTestResult(t, err, true, "invalid field 'Visits'")
+}
+
+func TestPresentFields(t *testing.T) {
+	t.Parallel()
+
+	rows := []models.Reports{
+		{ID: 1, Date: "2024-01-01", Visits: 10},
+		{ID: 2, Date: "2024-01-01", Browser: "Safari"},
+	}
+
+	expected := []string{"id", "report_name", "report_agency", "date", "browser", "visits"}
+	if fields := models.PresentFields(rows); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("PresentFields() = %v, want %v", fields, expected)
+	}
+}
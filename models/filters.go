@@ -0,0 +1,220 @@
+package models
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FilterOp is a comparison operator used in row filters.
+type FilterOp string
+
+const (
+	FilterEqual          FilterOp = "=="
+	FilterNotEqual       FilterOp = "!="
+	FilterGreater        FilterOp = ">"
+	FilterGreaterOrEqual FilterOp = ">="
+	FilterLess           FilterOp = "<"
+	FilterLessOrEqual    FilterOp = "<="
+	FilterContains       FilterOp = "contains"
+)
+
+// Sort orders accepted by ResultOptions.
+const (
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
+var filterPattern = regexp.MustCompile(`^\s*([a-z_]+)\s*(==|!=|>=|<=|=|>|<|\s+contains\s+)\s*(.*?)\s*$`)
+
+// Filter is a predicate on a single report field, such as `visits > 1000`.
+type Filter struct {
+	Field string
+	Op    FilterOp
+	Value string
+
+	number float64 // Value parsed as a number, for metric fields
+}
+
+// ParseFilter parses a filter expression of the form `field op value`. Supported operators are
+// ==, !=, >, >=, <, <= and contains; values may be wrapped in single or double quotes.
+// Dimension fields compare case-insensitively and metric fields compare numerically.
+func ParseFilter(expr string) (Filter, error) {
+	m := filterPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return Filter{}, fmt.Errorf("invalid filter '%s', expected 'field operator value' (e.g. visits > 1000)", expr)
+	}
+
+	filter := Filter{
+		Field: m[1],
+		Op:    FilterOp(strings.TrimSpace(m[2])),
+		Value: unquote(m[3]),
+	}
+	if filter.Op == "=" {
+		filter.Op = FilterEqual
+	}
+
+	switch {
+	case IsMetricField(filter.Field):
+		if filter.Op == FilterContains {
+			return Filter{}, fmt.Errorf("invalid filter '%s': contains only applies to text fields", expr)
+		}
+		number, err := strconv.ParseFloat(filter.Value, 64)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid filter '%s': %s is numeric, got '%s'", expr, filter.Field, filter.Value)
+		}
+		filter.number = number
+	case IsDimensionField(filter.Field):
+		// Text fields are compared as strings
+	default:
+		return Filter{}, fmt.Errorf("invalid filter '%s': unknown field '%s'", expr, filter.Field)
+	}
+
+	return filter, nil
+}
+
+// Match reports whether the row satisfies the filter.
+func (f Filter) Match(row *Reports) bool {
+	if value, ok := row.Metric(f.Field); ok {
+		return compareMatches(f.Op, cmp.Compare(value, f.number))
+	}
+
+	value, _ := row.Dimension(f.Field)
+	if f.Op == FilterContains {
+		return strings.Contains(strings.ToLower(value), strings.ToLower(f.Value))
+	}
+	return compareMatches(f.Op, strings.Compare(strings.ToLower(value), strings.ToLower(f.Value)))
+}
+
+// compareMatches reports whether a comparison result satisfies the operator.
+func compareMatches(op FilterOp, result int) bool {
+	switch op {
+	case FilterEqual:
+		return result == 0
+	case FilterNotEqual:
+		return result != 0
+	case FilterGreater:
+		return result > 0
+	case FilterGreaterOrEqual:
+		return result >= 0
+	case FilterLess:
+		return result < 0
+	case FilterLessOrEqual:
+		return result <= 0
+	case FilterContains:
+		return false
+	default:
+		return false
+	}
+}
+
+// unquote strips a matching pair of single or double quotes around value.
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// ResultOptions describes how fetched report rows are filtered, sorted and trimmed.
+type ResultOptions struct {
+	Filters    []Filter
+	SortBy     string
+	Descending bool
+	Top        int
+}
+
+// NewResultOptions parses and validates the filter, sort and top-N arguments of a report request.
+func NewResultOptions(filters []string, sortBy, sortOrder string, top int) (ResultOptions, error) {
+	options := ResultOptions{SortBy: sortBy, Top: top}
+
+	for _, expr := range filters {
+		filter, err := ParseFilter(expr)
+		if err != nil {
+			return ResultOptions{}, err
+		}
+		options.Filters = append(options.Filters, filter)
+	}
+
+	if sortBy != "" && !IsMetricField(sortBy) && !IsDimensionField(sortBy) {
+		return ResultOptions{}, fmt.Errorf("invalid sort_by field '%s'. Use a metric (%s) or a text field "+
+			"such as date", sortBy, strings.Join(MetricFieldNames(), ", "))
+	}
+
+	switch sortOrder {
+	case "", SortDescending:
+		options.Descending = true
+	case SortAscending:
+		options.Descending = false
+	default:
+		return ResultOptions{}, fmt.Errorf("invalid sort_order '%s', expected %s or %s",
+			sortOrder, SortAscending, SortDescending)
+	}
+
+	if top < 0 {
+		return ResultOptions{}, fmt.Errorf("top must be >= 1, got %d", top)
+	}
+	if top > 0 && sortBy == "" {
+		return ResultOptions{}, errors.New("top requires sort_by")
+	}
+
+	return options, nil
+}
+
+// IsZero reports whether the options leave the rows unchanged.
+func (o *ResultOptions) IsZero() bool {
+	return len(o.Filters) == 0 && o.SortBy == "" && o.Top == 0
+}
+
+// Apply returns the rows matching all filters, sorted and trimmed to the top N.
+// The input slice is not modified.
+func (o *ResultOptions) Apply(rows []Reports) []Reports {
+	result := make([]Reports, 0, len(rows))
+	for i := range rows {
+		if o.matches(&rows[i]) {
+			result = append(result, rows[i])
+		}
+	}
+
+	if o.SortBy != "" {
+		slices.SortStableFunc(result, func(x, y Reports) int {
+			c := compareField(&x, &y, o.SortBy)
+			if o.Descending {
+				return -c
+			}
+			return c
+		})
+	}
+
+	if o.Top > 0 && len(result) > o.Top {
+		result = result[:o.Top]
+	}
+
+	return result
+}
+
+// matches reports whether the row satisfies all filters.
+func (o *ResultOptions) matches(row *Reports) bool {
+	for _, filter := range o.Filters {
+		if !filter.Match(row) {
+			return false
+		}
+	}
+	return true
+}
+
+// compareField compares two rows by the given metric or dimension field.
+func compareField(x, y *Reports, field string) int {
+	if xValue, ok := x.Metric(field); ok {
+		yValue, _ := y.Metric(field)
+		return cmp.Compare(xValue, yValue)
+	}
+
+	xValue, _ := x.Dimension(field)
+	yValue, _ := y.Dimension(field)
+	return strings.Compare(xValue, yValue)
+}
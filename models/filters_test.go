@@ -0,0 +1,140 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+func TestParseFilter(t *testing.T) {
+	t.Parallel()
+
+	row := models.Reports{Country: "United States", Date: "2024-01-05", Visits: 1500, BounceRate: 0.42}
+
+	tests := []struct {
+		name    string
+		expr    string
+		matches bool
+	}{
+		{name: "text equality", expr: `country == "United States"`, matches: true},
+		{name: "text equality is case-insensitive", expr: "country = united states", matches: true},
+		{name: "text inequality", expr: "country != 'Canada'", matches: true},
+		{name: "text contains", expr: "country contains states", matches: true},
+		{name: "date comparison", expr: "date >= 2024-01-06", matches: false},
+		{name: "metric greater", expr: "visits > 1000", matches: true},
+		{name: "metric greater without spaces", expr: "visits>1500", matches: false},
+		{name: "metric greater or equal", expr: "visits >= 1500", matches: true},
+		{name: "metric less", expr: "bounce_rate < 0.5", matches: true},
+		{name: "metric less or equal", expr: "bounce_rate <= 0.4", matches: false},
+		{name: "metric equality", expr: "visits == 1500", matches: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := models.ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseFilter(%q) unexpected error: %v", tt.expr, err)
+			}
+			if got := filter.Match(&row); got != tt.matches {
+				t.Errorf("ParseFilter(%q).Match() = %v, want %v", tt.expr, got, tt.matches)
+			}
+		})
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		expr   string
+		errMsg string
+	}{
+		{name: "missing operator", expr: "visits 1000", errMsg: "expected 'field operator value'"},
+		{name: "unknown field", expr: "planet == mars", errMsg: "unknown field 'planet'"},
+		{name: "non-numeric metric value", expr: "visits > many", errMsg: "visits is numeric"},
+		{name: "contains on metric", expr: "visits contains 1", errMsg: "contains only applies to text fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := models.ParseFilter(tt.expr)
+			if err == nil {
+				t.Fatalf("ParseFilter(%q) expected error but got none", tt.expr)
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("ParseFilter(%q) error = %v, want error containing %q", tt.expr, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestNewResultOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		filters   []string
+		sortBy    string
+		sortOrder string
+		top       int
+		expectErr bool
+		errMsg    string
+	}{
+		{name: "no options", expectErr: false},
+		{name: "sort with top", sortBy: "visits", top: 10, expectErr: false},
+		{name: "sort by text field ascending", sortBy: "date", sortOrder: "asc", expectErr: false},
+		{name: "invalid filter", filters: []string{"visits"}, expectErr: true, errMsg: "invalid filter"},
+		{name: "invalid sort field", sortBy: "popularity", expectErr: true, errMsg: "invalid sort_by field"},
+		{name: "invalid sort order", sortBy: "visits", sortOrder: "up", expectErr: true, errMsg: "invalid sort_order"},
+		{name: "negative top", sortBy: "visits", top: -1, expectErr: true, errMsg: "top must be >= 1"},
+		{name: "top without sort", top: 5, expectErr: true, errMsg: "top requires sort_by"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := models.NewResultOptions(tt.filters, tt.sortBy, tt.sortOrder, tt.top)
+
+			validateTestResult(t, err, tt.expectErr, tt.errMsg)
+		})
+	}
+}
+
+func TestResultOptions_Apply(t *testing.T) {
+	t.Parallel()
+
+	rows := []models.Reports{
+		{ID: 1, Country: "Canada", Visits: 300},
+		{ID: 2, Country: "United States", Visits: 5000},
+		{ID: 3, Country: "Mexico", Visits: 800},
+		{ID: 4, Country: "Germany", Visits: 1200},
+	}
+
+	options, err := models.NewResultOptions([]string{"visits > 500"}, "visits", "", 2)
+	if err != nil {
+		t.Fatalf("NewResultOptions() unexpected error: %v", err)
+	}
+
+	result := options.Apply(rows)
+	if len(result) != 2 || result[0].ID != 2 || result[1].ID != 4 {
+		t.Errorf("Apply() = %+v, want rows 2 and 4", result)
+	}
+	if rows[0].ID != 1 {
+		t.Error("Apply() should not reorder the input rows")
+	}
+
+	ascending, err := models.NewResultOptions(nil, "country", "asc", 0)
+	if err != nil {
+		t.Fatalf("NewResultOptions() unexpected error: %v", err)
+	}
+	if result = ascending.Apply(rows); result[0].Country != "Canada" || result[3].Country != "United States" {
+		t.Errorf("Apply() = %+v, want countries in ascending order", result)
+	}
+}
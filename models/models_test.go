@@ -2,6 +2,7 @@ package models_test
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 	"time"
 
@@ -280,6 +281,24 @@ func TestReportArgs_JSON(t *testing.T) {
 			},
 			expected: `{"report_name":"traffic","limit":50}`,
 		},
+		{
+			name: "report args with agency",
+			args: models.ReportArgs{
+				ReportName: "devices",
+				AgencyName: "general-services-administration",
+			},
+			expected: `{"report_name":"devices","agency_name":"general-services-administration"}`,
+		},
+		{
+			name: "report args with filters and sorting",
+			args: models.ReportArgs{
+				ReportName: "countries",
+				Filters:    []string{"country == Canada"},
+				SortBy:     "visits",
+				Top:        5,
+			},
+			expected: `{"report_name":"countries","filters":["country == Canada"],"sort_by":"visits","top":5}`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -302,7 +321,7 @@ func TestReportArgs_JSON(t *testing.T) {
 				t.Errorf("Failed to unmarshal ReportArgs: %v", err)
 			}
 
-			if unmarshaled != tt.args {
+			if !reflect.DeepEqual(unmarshaled, tt.args) {
 				t.Errorf("Unmarshaled ReportArgs = %+v, want %+v", unmarshaled, tt.args)
 			}
 		})
@@ -487,6 +506,47 @@ func TestReportParams_Validate(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "valid single day range",
+			params: models.ReportParams{
+				After:  "2024-01-15",
+				Before: "2024-01-15",
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid after format",
+			params: models.ReportParams{
+				After: "01/15/2024",
+			},
+			expectErr: true,
+			errMsg:    "after must be a valid date in YYYY-MM-DD format",
+		},
+		{
+			name: "invalid before without zero padding",
+			params: models.ReportParams{
+				Before: "2024-1-5",
+			},
+			expectErr: true,
+			errMsg:    "before must be a valid date in YYYY-MM-DD format",
+		},
+		{
+			name: "invalid calendar date",
+			params: models.ReportParams{
+				After: "2024-02-30",
+			},
+			expectErr: true,
+			errMsg:    "after must be a valid date",
+		},
+		{
+			name: "after later than before",
+			params: models.ReportParams{
+				After:  "2024-02-01",
+				Before: "2024-01-31",
+			},
+			expectErr: true,
+			errMsg:    "after date 2024-02-01 must not be later than before date 2024-01-31",
+		},
 	}
 
 	for _, tt := range tests {
@@ -594,6 +654,64 @@ func TestReportRequest_JSON(t *testing.T) {
 	}
 }
 
+func TestReportRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		request   models.ReportRequest
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			name:      "valid request",
+			request:   models.ReportRequest{ReportName: "devices"},
+			expectErr: false,
+		},
+		{
+			name:      "valid agency request",
+			request:   models.ReportRequest{ReportName: "devices", AgencyName: "general-services-administration"},
+			expectErr: false,
+		},
+		{
+			name:      "valid domain request",
+			request:   models.ReportRequest{ReportName: "top-pages", Domain: "usa.gov"},
+			expectErr: false,
+		},
+		{
+			name:      "invalid report type",
+			request:   models.ReportRequest{ReportName: "unknown"},
+			expectErr: true,
+			errMsg:    "invalid report type 'unknown'",
+		},
+		{
+			name:      "agency and domain together",
+			request:   models.ReportRequest{ReportName: "devices", AgencyName: "gsa", Domain: "gsa.gov"},
+			expectErr: true,
+			errMsg:    "agency_name and domain cannot be used together",
+		},
+		{
+			name: "invalid parameters",
+			request: models.ReportRequest{
+				ReportName: "devices",
+				Parameters: models.ReportParams{Limit: 10001},
+			},
+			expectErr: true,
+			errMsg:    "limit must be between 1 and 10000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.request.Validate()
+
+			validateTestResult(t, err, tt.expectErr, tt.errMsg)
+		})
+	}
+}
+
 func TestReportResponse_JSON(t *testing.T) {
 	t.Parallel()
 
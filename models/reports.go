@@ -5,6 +5,7 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"time"
 )
@@ -12,10 +13,10 @@ import (
 // Reports represents the analytics report data structure.
 type Reports struct {
 	// Required fields
-	ID           int    `json:"id" jsonschema:"description=Generated unique identifier"`
-	ReportName   string `json:"report_name" jsonschema:"description=The name of the data point's report"`
-	ReportAgency string `json:"report_agency" jsonschema:"description=The name of the data point's agency"`
-	Date         string `json:"date" jsonschema:"description=The date the data in the data point corresponds to"`
+	ID           int    `json:"id" jsonschema:"Generated unique identifier"`
+	ReportName   string `json:"report_name" jsonschema:"The name of the data point's report"`
+	ReportAgency string `json:"report_agency" jsonschema:"The name of the data point's agency"`
+	Date         string `json:"date" jsonschema:"The date the data in the data point corresponds to"`
 
 	// Optional fields - depend on the report requested
 	ActiveVisitors             int     `json:"active_visitors,omitempty"`
@@ -24,7 +25,7 @@ type Reports struct {
 	Browser                    string  `json:"browser,omitempty"`
 	City                       string  `json:"city,omitempty"`
 	Country                    string  `json:"country,omitempty"`
-	Device                     string  `json:"device,omitempty" jsonschema:"description=the type of device of the visitor"`
+	Device                     string  `json:"device,omitempty" jsonschema:"the type of device of the visitor"`
 	Domain                     string  `json:"domain,omitempty"`
 	EventLabel                 string  `json:"event_label,omitempty"`
 	FileName                   string  `json:"file_name,omitempty"`
@@ -33,9 +34,9 @@ type Reports struct {
 	Language                   string  `json:"language,omitempty"`
 	LanguageCode               string  `json:"language_code,omitempty"`
 	MobileDevice               string  `json:"mobile_device,omitempty"`
-	OS                         string  `json:"os,omitempty" jsonschema:"description=the operating system of the visitor"`
-	OSVersion                  string  `json:"os_version,omitempty" jsonschema:"description=the operating system version"`
-	Page                       string  `json:"page,omitempty" jsonschema:"description=the path of the page visited"`
+	OS                         string  `json:"os,omitempty" jsonschema:"the operating system of the visitor"`
+	OSVersion                  string  `json:"os_version,omitempty" jsonschema:"the operating system version"`
+	Page                       string  `json:"page,omitempty" jsonschema:"the path of the page visited"`
 	PageTitle                  string  `json:"page_title,omitempty"`
 	Pageviews                  int     `json:"pageviews,omitempty"`
 	PageviewsPerSession        int     `json:"pageviews_per_session,omitempty"`
@@ -49,17 +50,17 @@ type Reports struct {
 
 // Error represents an API error response.
 type Error struct {
-	Message        string `json:"message" jsonschema:"description=Error message"`
-	RequiredFields string `json:"required_fields,omitempty" jsonschema:"description=Required fields that are missing"`
-	Example        string `json:"example,omitempty" jsonschema:"description=Example of correct usage"`
+	Message        string `json:"message" jsonschema:"Error message"`
+	RequiredFields string `json:"required_fields,omitempty" jsonschema:"Required fields that are missing"`
+	Example        string `json:"example,omitempty" jsonschema:"Example of correct usage"`
 }
 
 // ReportParams represents query parameters for report requests.
 type ReportParams struct {
-	Limit  int    `json:"limit,omitempty" jsonschema:"description=Limit the number of data points (max 10,000)"`
-	Page   int    `json:"page,omitempty" jsonschema:"description=Pages through results (e.g., page=2 for next 1000)"`
-	After  string `json:"after,omitempty" jsonschema:"description=Limit results to dates on or after (YYYY-MM-DD)"`
-	Before string `json:"before,omitempty" jsonschema:"description=Limit results to dates on or before (YYYY-MM-DD)"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"Limit the number of data points (max 10,000)"`
+	Page   int    `json:"page,omitempty" jsonschema:"Pages through results (e.g., page=2 for next 1000)"`
+	After  string `json:"after,omitempty" jsonschema:"Limit results to dates on or after (YYYY-MM-DD)"`
+	Before string `json:"before,omitempty" jsonschema:"Limit results to dates on or before (YYYY-MM-DD)"`
 }
 
 // ValidateReportParams validates the report parameters.
@@ -72,27 +73,94 @@ func (p *ReportParams) Validate() error {
 		return fmt.Errorf("page must be >= 1, got %d", p.Page)
 	}
 
-	// TODO: Add date format validation for After and Before
-	// Expected format: YYYY-MM-DD
+	after, err := parseParamDate("after", p.After)
+	if err != nil {
+		return err
+	}
+
+	before, err := parseParamDate("before", p.Before)
+	if err != nil {
+		return err
+	}
+
+	if !after.IsZero() && !before.IsZero() && after.After(before) {
+		return fmt.Errorf("after date %s must not be later than before date %s", p.After, p.Before)
+	}
 
 	return nil
 }
 
+// ResolveDates replaces date expressions such as "last 7 days" or "2024-Q1" in After and Before
+// with concrete dates relative to now. After takes the start of its range and Before the end.
+func (p *ReportParams) ResolveDates(now time.Time) error {
+	if p.After != "" {
+		dates, err := ParseDateExpression(p.After, now)
+		if err != nil {
+			return fmt.Errorf("invalid after: %w", err)
+		}
+		p.After = dates.After()
+	}
+
+	if p.Before != "" {
+		dates, err := ParseDateExpression(p.Before, now)
+		if err != nil {
+			return fmt.Errorf("invalid before: %w", err)
+		}
+		p.Before = dates.Before()
+	}
+
+	return nil
+}
+
+// parseParamDate parses an optional date parameter in the strict YYYY-MM-DD format.
+func parseParamDate(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a valid date in YYYY-MM-DD format, got '%s'", name, value)
+	}
+	return date, nil
+}
+
 // ReportRequest represents a request for analytics data.
 type ReportRequest struct {
-	ReportName string       `json:"report_name" jsonschema:"description=Name of the report"`
-	AgencyName string       `json:"agency_name,omitempty" jsonschema:"description=Name of the agency"`
-	Domain     string       `json:"domain,omitempty" jsonschema:"description=Name of the domain"`
-	Parameters ReportParams `json:"parameters" jsonschema:"description=Query parameters"`
+	ReportName string       `json:"report_name" jsonschema:"Name of the report"`
+	AgencyName string       `json:"agency_name,omitempty" jsonschema:"Name of the agency"`
+	Domain     string       `json:"domain,omitempty" jsonschema:"Name of the domain"`
+	Parameters ReportParams `json:"parameters" jsonschema:"Query parameters"`
+}
+
+// Validate validates the report request, including its query parameters.
+func (r *ReportRequest) Validate() error {
+	if !ReportType(r.ReportName).IsValid() {
+		return fmt.Errorf("invalid report type '%s'. Valid types: %v", r.ReportName, GetAllReportTypes())
+	}
+
+	if r.AgencyName != "" && r.Domain != "" {
+		return errors.New("agency_name and domain cannot be used together")
+	}
+
+	return r.Parameters.Validate()
+}
+
+// Pagination describes how a multi-page report fetch was performed.
+type Pagination struct {
+	PagesRead int  `json:"pages_read" jsonschema:"Number of pages fetched from the API"`
+	Records   int  `json:"records" jsonschema:"Number of records returned"`
+	Truncated bool `json:"truncated" jsonschema:"Whether more records were available beyond max_records"`
 }
 
 // ReportResponse represents the response containing analytics data.
 type ReportResponse struct {
-	Data  []Reports `json:"data" jsonschema:"description=Array of report data"`
-	Error *Error    `json:"error,omitempty" jsonschema:"description=Error information if request failed"`
+	Data       []Reports   `json:"data" jsonschema:"Array of report data"`
+	Pagination *Pagination `json:"pagination,omitempty" jsonschema:"Pagination details for multi-page fetches"`
+	Error      *Error      `json:"error,omitempty" jsonschema:"Error information if request failed"`
 }
 
 // ParseDate helper function to parse the date string into time.Time.
 func (r *Reports) ParseDate() (time.Time, error) {
-	return time.Parse("2006-01-02", r.Date)
+	return time.Parse(DateLayout, r.Date)
 }
@@ -31,42 +31,86 @@ func (rt ReportType) String() string {
 
 // IsValid checks if the report type is valid.
 func (rt ReportType) IsValid() bool {
-	switch rt {
-	case ReportTypeDevices, ReportTypeBrowsers, ReportTypeOperatingSystems,
-		ReportTypeLanguages, ReportTypeCountries, ReportTypeCities,
-		ReportTypeTraffic, ReportTypeTopPages, ReportTypeDownloads,
-		ReportTypeActiveUsers, ReportTypeSources, ReportTypeDomains,
-		ReportTypeAgencies:
-		return true
-	default:
-		return false
-	}
+	_, ok := LookupReport(string(rt))
+	return ok
+}
+
+// DefaultDimension returns the field that identifies a row within a single day of the report,
+// or an empty string when the report has one row per day.
+func (rt ReportType) DefaultDimension() string {
+	info, _ := LookupReport(string(rt))
+	return info.DefaultDimension
 }
 
 // GetAllReportTypes returns all available report types.
 func GetAllReportTypes() []ReportType {
-	return []ReportType{
-		ReportTypeDevices,
-		ReportTypeBrowsers,
-		ReportTypeOperatingSystems,
-		ReportTypeLanguages,
-		ReportTypeCountries,
-		ReportTypeCities,
-		ReportTypeTraffic,
-		ReportTypeTopPages,
-		ReportTypeDownloads,
-		ReportTypeActiveUsers,
-		ReportTypeSources,
-		ReportTypeDomains,
-		ReportTypeAgencies,
+	types := make([]ReportType, len(reportCatalog))
+	for i, info := range reportCatalog {
+		types[i] = info.Name
 	}
+	return types
 }
 
 // ReportArgs represents the arguments for fetching a report.
 type ReportArgs struct {
-	ReportName string `json:"report_name" jsonschema:"required" jsonschema_description:"Name of the report"`
-	Limit      int    `json:"limit,omitempty" jsonschema_description:"Limit results (1-10000, default 1000)"`
-	Page       int    `json:"page,omitempty" jsonschema_description:"Page number (default 1)"`
-	After      string `json:"after,omitempty" jsonschema_description:"Start date (YYYY-MM-DD format)"`
-	Before     string `json:"before,omitempty" jsonschema_description:"End date (YYYY-MM-DD format)"`
+	ReportName   string   `json:"report_name" jsonschema:"required" jsonschema_description:"Name of the report"`
+	Limit        int      `json:"limit,omitempty" jsonschema_description:"Limit results (1-10000, default 1000)"`
+	Page         int      `json:"page,omitempty" jsonschema_description:"Page number (default 1)"`
+	After        string   `json:"after,omitempty" jsonschema_description:"Start date (YYYY-MM-DD or expression)"`
+	Before       string   `json:"before,omitempty" jsonschema_description:"End date (YYYY-MM-DD or expression)"`
+	DateRange    string   `json:"date_range,omitempty" jsonschema_description:"Whole date range, e.g. last 7 days"`
+	AgencyName   string   `json:"agency_name,omitempty" jsonschema_description:"Restrict results to a single agency"`
+	Domain       string   `json:"domain,omitempty" jsonschema_description:"Restrict results to a single domain"`
+	FetchAll     bool     `json:"fetch_all,omitempty" jsonschema_description:"Fetch all pages up to max_records"`
+	MaxRecords   int      `json:"max_records,omitempty" jsonschema_description:"Record budget for multi-page fetches"`
+	Filters      []string `json:"filters,omitempty" jsonschema_description:"Row predicates, e.g. visits > 1000"`
+	SortBy       string   `json:"sort_by,omitempty" jsonschema_description:"Field to sort rows by, e.g. visits"`
+	SortOrder    string   `json:"sort_order,omitempty" jsonschema_description:"Sort order: asc or desc (default desc)"`
+	Top          int      `json:"top,omitempty" jsonschema_description:"Keep only the first N rows after sorting"`
+	Fields       []string `json:"fields,omitempty" jsonschema_description:"Row fields to return, e.g. date, visits"`
+	Compact      bool     `json:"compact,omitempty" jsonschema_description:"Return compact, non-indented JSON"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema_description:"json, csv, markdown or ndjson"`
+	EmbedCSV     bool     `json:"embed_csv,omitempty" jsonschema_description:"Also attach the rows as a text/csv resource"`
+}
+
+// AggregateArgs represents the arguments for aggregating a report.
+type AggregateArgs struct {
+	ReportName string   `json:"report_name" jsonschema:"required" jsonschema_description:"Name of the report"`
+	GroupBy    string   `json:"group_by" jsonschema:"required" jsonschema_description:"Field to group rows by"`
+	Metrics    []string `json:"metrics,omitempty" jsonschema_description:"Metric fields to summarize"`
+	Functions  []string `json:"functions,omitempty" jsonschema_description:"Functions: sum, avg, min, max, count"`
+	After      string   `json:"after,omitempty" jsonschema_description:"Start date (YYYY-MM-DD or expression)"`
+	Before     string   `json:"before,omitempty" jsonschema_description:"End date (YYYY-MM-DD or expression)"`
+	DateRange  string   `json:"date_range,omitempty" jsonschema_description:"Whole date range, e.g. last 7 days"`
+	AgencyName string   `json:"agency_name,omitempty" jsonschema_description:"Restrict results to a single agency"`
+	Domain     string   `json:"domain,omitempty" jsonschema_description:"Restrict results to a single domain"`
+	MaxRecords int      `json:"max_records,omitempty" jsonschema_description:"Record budget for the fetch"`
+}
+
+// CompareArgs represents the arguments for comparing a report across two periods.
+type CompareArgs struct {
+	ReportName     string   `json:"report_name" jsonschema:"required" jsonschema_description:"Name of the report"`
+	Period         string   `json:"period" jsonschema:"required" jsonschema_description:"Current period, e.g. last month"`
+	PreviousPeriod string   `json:"previous_period,omitempty" jsonschema_description:"Period to compare against"`
+	Dimension      string   `json:"dimension,omitempty" jsonschema_description:"Field to join rows on, e.g. device"`
+	Metrics        []string `json:"metrics,omitempty" jsonschema_description:"Metric fields to compare"`
+	AgencyName     string   `json:"agency_name,omitempty" jsonschema_description:"Restrict results to a single agency"`
+	Domain         string   `json:"domain,omitempty" jsonschema_description:"Restrict results to a single domain"`
+	MaxRecords     int      `json:"max_records,omitempty" jsonschema_description:"Record budget for each period"`
+	Top            int      `json:"top,omitempty" jsonschema_description:"Keep the N rows with the largest changes"`
+}
+
+// AnomalyArgs represents the arguments for detecting anomalies in a daily report series.
+type AnomalyArgs struct {
+	ReportName string  `json:"report_name" jsonschema:"required" jsonschema_description:"Date-keyed report, e.g. traffic"`
+	Metric     string  `json:"metric,omitempty" jsonschema_description:"Metric to build the daily series from"`
+	Method     string  `json:"method,omitempty" jsonschema_description:"Detection method: zscore or iqr"`
+	Window     int     `json:"window,omitempty" jsonschema_description:"Preceding days each day is compared to"`
+	Threshold  float64 `json:"threshold,omitempty" jsonschema_description:"Band width in stddevs or IQRs"`
+	After      string  `json:"after,omitempty" jsonschema_description:"Start date (YYYY-MM-DD or expression)"`
+	Before     string  `json:"before,omitempty" jsonschema_description:"End date (YYYY-MM-DD or expression)"`
+	DateRange  string  `json:"date_range,omitempty" jsonschema_description:"Whole date range, e.g. last 30 days"`
+	AgencyName string  `json:"agency_name,omitempty" jsonschema_description:"Restrict results to a single agency"`
+	Domain     string  `json:"domain,omitempty" jsonschema_description:"Restrict results to a single domain"`
+	MaxRecords int     `json:"max_records,omitempty" jsonschema_description:"Record budget for the fetch"`
 }
@@ -0,0 +1,69 @@
+package prompts
+
+import (
+	"fmt"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+// Prompt argument types. An argument without a type accepts any text.
+const (
+	ArgumentTypeReport = "report"
+	ArgumentTypeMonth  = "month"
+	ArgumentTypeYear   = "year"
+)
+
+// argumentValidators check the value of a typed argument and return its normalized form.
+var argumentValidators = map[string]func(string) (string, error){
+	ArgumentTypeReport: validateReportArgument,
+	ArgumentTypeMonth:  validateMonthArgument,
+	ArgumentTypeYear:   validateYearArgument,
+}
+
+// templateFuncs are the functions available to prompt templates in addition to the built-in ones.
+var templateFuncs = template.FuncMap{
+	"lastDay": lastDayOfMonth,
+}
+
+// validateReportArgument checks that the value names a report type.
+func validateReportArgument(value string) (string, error) {
+	if !models.ReportType(value).IsValid() {
+		return "", fmt.Errorf("unknown report '%s', valid reports: %v", value, models.GetAllReportTypes())
+	}
+	return value, nil
+}
+
+// validateMonthArgument checks that the value is a month number from 1 to 12 and returns it as two digits.
+func validateMonthArgument(value string) (string, error) {
+	month, err := strconv.Atoi(value)
+	if err != nil || month < 1 || month > 12 {
+		return "", fmt.Errorf("invalid month '%s', must be 01-12", value)
+	}
+	return fmt.Sprintf("%02d", month), nil
+}
+
+// validateYearArgument checks that the value is a four-digit year.
+func validateYearArgument(value string) (string, error) {
+	year, err := strconv.Atoi(value)
+	if err != nil || len(value) != 4 || year < 1 {
+		return "", fmt.Errorf("invalid year '%s', must be a four-digit year such as 2024", value)
+	}
+	return value, nil
+}
+
+// lastDayOfMonth returns the last day of a month as two digits, given a validated year and month.
+func lastDayOfMonth(year, month string) (string, error) {
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return "", err
+	}
+	m, err := strconv.Atoi(month)
+	if err != nil {
+		return "", err
+	}
+	// Day 0 of the next month is the last day of this month.
+	return fmt.Sprintf("%02d", time.Date(y, time.Month(m)+1, 0, 0, 0, 0, 0, time.UTC).Day()), nil
+}
@@ -2,170 +2,219 @@ package prompts
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// PromptRegistry is the part of the MCP server that prompts are registered with.
+// Adding and removing prompts notifies clients that the prompt list changed.
+type PromptRegistry interface {
+	AddPrompt(p *mcp.Prompt, h mcp.PromptHandler)
+	RemovePrompts(names ...string)
+}
+
 // ReportPrompts handles report-related prompt operations.
 type ReportPrompts struct {
 	logger *slog.Logger
+
+	// dir is the directory of prompt templates overriding the built-in ones, empty if none.
+	dir string
+
+	mu         sync.Mutex
+	templates  map[string]*PromptTemplate
+	registered map[string]*PromptTemplate
+	dirState   string
 }
 
-// NewReportPrompts creates a new ReportPrompts with the provided logger.
-func NewReportPrompts(logger *slog.Logger) *ReportPrompts {
-	return &ReportPrompts{
-		logger: logger,
+// NewReportPromptsWithDir creates a new ReportPrompts whose built-in prompt templates are
+// overridden and extended by the *.tmpl files in dir. An empty dir serves the built-in templates only.
+func NewReportPromptsWithDir(logger *slog.Logger, dir string) (*ReportPrompts, error) {
+	templates, err := LoadPromptTemplates(dir)
+	if err != nil {
+		return nil, err
 	}
-}
 
-// AnalyzeTrafficPrompt provides guidance for analyzing traffic data.
-func (rp *ReportPrompts) AnalyzeTrafficPrompt(_ context.Context, _ *mcp.ServerSession,
-	params *mcp.GetPromptParams) (*mcp.GetPromptResult, error) {
-	dateRange := params.Arguments["date_range"]
-	if dateRange == "" {
-		dateRange = "last 30 days"
+	rp := &ReportPrompts{
+		logger:     logger,
+		dir:        dir,
+		templates:  templates,
+		registered: make(map[string]*PromptTemplate),
 	}
+	rp.dirState, _ = rp.templateDirState()
+	return rp, nil
+}
 
-	rp.logger.Info("Processing analyze traffic prompt", "date_range", dateRange)
+// Prompts returns the declarations of all prompts, sorted by name.
+func (rp *ReportPrompts) Prompts() []*mcp.Prompt {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
 
-	return &mcp.GetPromptResult{
-		Description: "Analyze website traffic patterns and trends",
-		Messages: []*mcp.PromptMessage{
-			{
-				Role: "user",
-				Content: &mcp.TextContent{
-					Text: `Analyze the website traffic data for ` + dateRange + `. Please:
-
-1. Use get_report("traffic") to fetch overall traffic metrics
-2. Use get_report("devices") to understand device usage patterns
-3. Use get_report("browsers") to see browser preferences
-4. Use get_report("top-pages") to identify most popular content
-
-Provide insights on:
-- Traffic trends and patterns
-- User behavior and preferences
-- Device and browser usage
-- Content performance
-- Recommendations for improvement`,
-				},
-			},
-		},
-	}, nil
+	list := make([]*mcp.Prompt, 0, len(rp.templates))
+	for _, pt := range rp.templates {
+		list = append(list, pt.Prompt())
+	}
+	slices.SortFunc(list, func(a, b *mcp.Prompt) int { return strings.Compare(a.Name, b.Name) })
+	return list
 }
 
-// CompareReportsPrompt helps compare different report types.
-func (rp *ReportPrompts) CompareReportsPrompt(_ context.Context, _ *mcp.ServerSession,
-	params *mcp.GetPromptParams) (*mcp.GetPromptResult, error) {
-	report1 := params.Arguments["report1"]
-	report2 := params.Arguments["report2"]
+// ArgumentType returns the declared type of a prompt argument, or an empty string if the prompt or
+// argument does not exist or the argument accepts any text.
+func (rp *ReportPrompts) ArgumentType(prompt, argument string) string {
+	rp.mu.Lock()
+	pt, ok := rp.templates[prompt]
+	rp.mu.Unlock()
+	if !ok {
+		return ""
+	}
 
-	if report1 == "" {
-		report1 = "devices"
+	for _, arg := range pt.Arguments {
+		if arg.Name == argument {
+			return arg.Type
+		}
 	}
-	if report2 == "" {
-		report2 = "browsers"
+	return ""
+}
+
+// GetPrompt renders the prompt named in the request with its arguments.
+func (rp *ReportPrompts) GetPrompt(_ context.Context, _ *mcp.ServerSession,
+	params *mcp.GetPromptParams) (*mcp.GetPromptResult, error) {
+	rp.mu.Lock()
+	pt, ok := rp.templates[params.Name]
+	rp.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown prompt %q", params.Name)
 	}
 
-	rp.logger.Info("Processing compare reports prompt", "report1", report1, "report2", report2)
+	rp.logger.Info("Processing prompt", "name", params.Name, "arguments", params.Arguments)
+
+	text, err := pt.Render(params.Arguments)
+	if err != nil {
+		rp.logger.Error("Failed to render prompt", "name", params.Name, "error", err)
+		return nil, fmt.Errorf("invalid arguments for prompt %q: %w", params.Name, err)
+	}
 
 	return &mcp.GetPromptResult{
-		Description: "Compare and analyze two different report types",
+		Description: pt.Description,
 		Messages: []*mcp.PromptMessage{
 			{
-				Role: "user",
-				Content: &mcp.TextContent{
-					Text: `Compare ` + report1 + ` and ` + report2 + ` reports. Please:
-
-1. Use get_report("` + report1 + `") to fetch the first report
-2. Use get_report("` + report2 + `") to fetch the second report
-3. Analyze the data from both reports
-
-Provide a comparative analysis including:
-- Key metrics from each report
-- Trends and patterns observed
-- Correlations between the two data sets
-- Actionable insights and recommendations
-- Data visualization suggestions`,
-				},
+				Role:    "user",
+				Content: &mcp.TextContent{Text: text},
 			},
 		},
 	}, nil
 }
 
-// MonthlyReportPrompt generates comprehensive monthly analytics.
-func (rp *ReportPrompts) MonthlyReportPrompt(_ context.Context, _ *mcp.ServerSession,
-	params *mcp.GetPromptParams) (*mcp.GetPromptResult, error) {
-	month := params.Arguments["month"]
-	year := params.Arguments["year"]
+// Register adds the prompts that are new or changed since the last call to the registry and
+// removes the prompts whose templates are gone.
+func (rp *ReportPrompts) Register(registry PromptRegistry) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	for name, pt := range rp.templates {
+		if previous, ok := rp.registered[name]; ok && previous.source == pt.source {
+			continue
+		}
+		rp.logger.Info("Registering prompt", "name", name)
+		registry.AddPrompt(pt.Prompt(), rp.GetPrompt)
+		rp.registered[name] = pt
+	}
+
+	var removed []string
+	for name := range rp.registered {
+		if _, ok := rp.templates[name]; !ok {
+			removed = append(removed, name)
+			delete(rp.registered, name)
+		}
+	}
+	if len(removed) > 0 {
+		rp.logger.Info("Removing prompts", "names", removed)
+		registry.RemovePrompts(removed...)
+	}
+}
 
-	if month == "" || year == "" {
-		month = "01"
-		year = "2024"
+// Reload reloads the prompt templates and updates the registry. If a template fails to load, the
+// previous templates stay in effect.
+func (rp *ReportPrompts) Reload(registry PromptRegistry) error {
+	templates, err := LoadPromptTemplates(rp.dir)
+	if err != nil {
+		return err
 	}
 
-	rp.logger.Info("Processing monthly report prompt", "month", month, "year", year)
+	rp.mu.Lock()
+	rp.templates = templates
+	rp.mu.Unlock()
 
-	return &mcp.GetPromptResult{
-		Description: "Generate comprehensive monthly analytics report",
-		Messages: []*mcp.PromptMessage{
-			{
-				Role: "user",
-				Content: &mcp.TextContent{
-					Text: buildMonthlyReportText(month, year),
-				},
-			},
-		},
-	}, nil
+	rp.Register(registry)
+	return nil
 }
 
-// buildMonthlyReportText builds the monthly report prompt text.
-func buildMonthlyReportText(month, year string) string {
-	dateRange := `after="` + year + `-` + month + `-01", before="` + year + `-` + month + `-31"`
-
-	return `Generate a comprehensive monthly analytics report for ` + month + `/` + year + `. Please:
-
-1. Use get_report("traffic", ` + dateRange + `) for traffic data
-2. Use get_report("devices", ` + dateRange + `) for device breakdown
-3. Use get_report("top-pages", ` + dateRange + `) for content performance
-4. Use get_report("countries", ` + dateRange + `) for geographic data
-
-Create a comprehensive report with:
-- Executive summary of key metrics
-- Traffic trends and growth patterns
-- User demographics and behavior
-- Content performance analysis
-- Geographic distribution insights
-- Month-over-month comparisons (if available)
-- Recommendations for the next month`
+// WatchTemplates checks the template directory for changes every interval until the context is
+// canceled, and reloads the prompts when a template file was added, changed or removed.
+func (rp *ReportPrompts) WatchTemplates(ctx context.Context, registry PromptRegistry, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rp.reloadIfChanged(ctx, registry)
+		}
+	}
 }
 
-// RealTimeInsightsPrompt provides real-time analytics guidance.
-func (rp *ReportPrompts) RealTimeInsightsPrompt(_ context.Context, _ *mcp.ServerSession,
-	_ *mcp.GetPromptParams) (*mcp.GetPromptResult, error) {
-	rp.logger.Info("Processing real-time insights prompt")
+// reloadIfChanged reloads the prompts if the template directory changed since the last check.
+func (rp *ReportPrompts) reloadIfChanged(ctx context.Context, registry PromptRegistry) {
+	state, err := rp.templateDirState()
+	if err != nil {
+		rp.logger.WarnContext(ctx, "Failed to scan prompt templates directory", "error", err)
+		return
+	}
+	if state == rp.dirState {
+		return
+	}
+	rp.dirState = state
 
-	return &mcp.GetPromptResult{
-		Description: "Get real-time website analytics and insights",
-		Messages: []*mcp.PromptMessage{
-			{
-				Role: "user",
-				Content: &mcp.TextContent{
-					Text: `Provide real-time analytics insights. Please:
-
-1. Use get_report("realtime") to get current active users
-2. Use get_report("traffic") to get recent traffic trends
-3. Use get_report("top-pages") to see what content is currently popular
-
-Analyze and provide:
-- Current website activity levels
-- Real-time user engagement
-- Popular content right now
-- Traffic patterns compared to historical data
-- Immediate optimization opportunities
-- Alert-worthy trends or anomalies`,
-				},
-			},
-		},
-	}, nil
+	rp.logger.InfoContext(ctx, "Prompt templates changed, reloading", "dir", rp.dir)
+	if err = rp.Reload(registry); err != nil {
+		rp.logger.WarnContext(ctx, "Failed to reload prompt templates, keeping the previous ones", "error", err)
+	}
+}
+
+// templateDirState summarizes the names, sizes and modification times of the template files, so
+// that any change to them changes the summary.
+func (rp *ReportPrompts) templateDirState() (string, error) {
+	if rp.dir == "" {
+		return "", nil
+	}
+
+	entries, err := os.ReadDir(rp.dir)
+	if err != nil {
+		return "", err
+	}
+
+	var state strings.Builder
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != templateExt {
+			continue
+		}
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			if os.IsNotExist(infoErr) {
+				continue
+			}
+			return "", infoErr
+		}
+		fmt.Fprintf(&state, "%s:%d:%d;", entry.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return state.String(), nil
 }
@@ -11,14 +11,22 @@ import (
 	"github.com/rameshsunkara/go-mcp-example/prompts"
 )
 
-func TestNewReportPrompts(t *testing.T) {
+// newReportPrompts creates a ReportPrompts serving the built-in prompt templates.
+func newReportPrompts(t *testing.T, logger *slog.Logger) *prompts.ReportPrompts {
+	t.Helper()
+	rp, err := prompts.NewReportPromptsWithDir(logger, "")
+	if err != nil {
+		t.Fatalf("NewReportPromptsWithDir() unexpected error: %v", err)
+	}
+	return rp
+}
+
+func TestNewReportPromptsWithDir(t *testing.T) {
 	t.Parallel()
 
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
-	rp := prompts.NewReportPrompts(logger)
-
-	if rp == nil {
-		t.Error("NewReportPrompts() returned nil")
+	if rp := newReportPrompts(t, logger); rp == nil {
+		t.Error("NewReportPromptsWithDir() returned nil")
 	}
 }
 
@@ -26,7 +34,7 @@ func TestAnalyzeTrafficPrompt(t *testing.T) {
 	t.Parallel()
 
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
-	rp := prompts.NewReportPrompts(logger)
+	rp := newReportPrompts(t, logger)
 
 	tests := []struct {
 		name        string
@@ -60,10 +68,11 @@ func testAnalyzeTrafficPromptCase(t *testing.T, rp *prompts.ReportPrompts, tt st
 	expectInMsg string
 }) {
 	params := &mcp.GetPromptParams{
+		Name:      "analyze-traffic",
 		Arguments: tt.arguments,
 	}
 
-	result, err := rp.AnalyzeTrafficPrompt(context.Background(), nil, params)
+	result, err := rp.GetPrompt(context.Background(), nil, params)
 	if err != nil {
 		t.Errorf("AnalyzeTrafficPrompt() error = %v", err)
 		return
@@ -78,7 +87,7 @@ func TestCompareReportsPrompt(t *testing.T) {
 	t.Parallel()
 
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
-	rp := prompts.NewReportPrompts(logger)
+	rp := newReportPrompts(t, logger)
 
 	tests := []struct {
 		name        string
@@ -107,10 +116,11 @@ func TestCompareReportsPrompt(t *testing.T) {
 			t.Parallel()
 
 			params := &mcp.GetPromptParams{
+				Name:      "compare-reports",
 				Arguments: tt.arguments,
 			}
 
-			result, err := rp.CompareReportsPrompt(context.Background(), nil, params)
+			result, err := rp.GetPrompt(context.Background(), nil, params)
 			if err != nil {
 				t.Errorf("CompareReportsPrompt() error = %v", err)
 				return
@@ -145,7 +155,7 @@ func TestMonthlyReportPrompt(t *testing.T) {
 	t.Parallel()
 
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
-	rp := prompts.NewReportPrompts(logger)
+	rp := newReportPrompts(t, logger)
 
 	tests := []struct {
 		name        string
@@ -158,9 +168,9 @@ func TestMonthlyReportPrompt(t *testing.T) {
 			expectInMsg: []string{"03/2024", "2024-03-01", "2024-03-31"},
 		},
 		{
-			name:        "with defaults when not specified",
-			arguments:   map[string]string{},
-			expectInMsg: []string{"01/2024", "2024-01-01", "2024-01-31"},
+			name:        "with single-digit month in a leap year",
+			arguments:   map[string]string{"month": "2", "year": "2024"},
+			expectInMsg: []string{"02/2024", "2024-02-01", "2024-02-29"},
 		},
 	}
 
@@ -179,10 +189,11 @@ func testMonthlyReportPromptCase(t *testing.T, rp *prompts.ReportPrompts, tt str
 	expectInMsg []string
 }) {
 	params := &mcp.GetPromptParams{
+		Name:      "monthly-report",
 		Arguments: tt.arguments,
 	}
 
-	result, err := rp.MonthlyReportPrompt(context.Background(), nil, params)
+	result, err := rp.GetPrompt(context.Background(), nil, params)
 	if err != nil {
 		t.Errorf("MonthlyReportPrompt() error = %v", err)
 		return
@@ -193,6 +204,128 @@ func testMonthlyReportPromptCase(t *testing.T, rp *prompts.ReportPrompts, tt str
 	validateReportCalls(t, result, []string{"traffic", "devices", "top-pages", "countries"}, "MonthlyReportPrompt")
 }
 
+func TestPromptArgumentValidation(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	rp := newReportPrompts(t, logger)
+
+	tests := []struct {
+		name      string
+		prompt    string
+		arguments map[string]string
+		errMsg    string
+	}{
+		{
+			name:      "monthly report without arguments",
+			prompt:    "monthly-report",
+			arguments: map[string]string{},
+			errMsg:    `missing required argument "month"`,
+		},
+		{
+			name:      "monthly report without year",
+			prompt:    "monthly-report",
+			arguments: map[string]string{"month": "03"},
+			errMsg:    `missing required argument "year"`,
+		},
+		{
+			name:      "month out of range",
+			prompt:    "monthly-report",
+			arguments: map[string]string{"month": "13", "year": "2024"},
+			errMsg:    "invalid month '13', must be 01-12",
+		},
+		{
+			name:      "month name",
+			prompt:    "monthly-report",
+			arguments: map[string]string{"month": "March", "year": "2024"},
+			errMsg:    "invalid month 'March'",
+		},
+		{
+			name:      "two-digit year",
+			prompt:    "monthly-report",
+			arguments: map[string]string{"month": "03", "year": "24"},
+			errMsg:    "invalid year '24'",
+		},
+		{
+			name:      "unknown report",
+			prompt:    "compare-reports",
+			arguments: map[string]string{"report1": "devices", "report2": "weather"},
+			errMsg:    "unknown report 'weather'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			params := &mcp.GetPromptParams{Name: tt.prompt, Arguments: tt.arguments}
+			_, err := rp.GetPrompt(context.Background(), nil, params)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("GetPrompt(%s) error = %v, want error containing %q", tt.prompt, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestPromptArgumentDeclarations(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	rp := newReportPrompts(t, logger)
+
+	expected := map[string]map[string]bool{
+		"analyze-traffic":   {"date_range": false},
+		"compare-reports":   {"report1": false, "report2": false},
+		"monthly-report":    {"month": true, "year": true},
+		"realtime-insights": {},
+	}
+
+	list := rp.Prompts()
+	if len(list) != len(expected) {
+		t.Errorf("Prompts() returned %d prompts, want %d", len(list), len(expected))
+	}
+	for _, prompt := range list {
+		args, ok := expected[prompt.Name]
+		if !ok {
+			t.Errorf("Unexpected prompt %q", prompt.Name)
+			continue
+		}
+		if len(prompt.Arguments) != len(args) {
+			t.Errorf("%s declares %d arguments, want %d", prompt.Name, len(prompt.Arguments), len(args))
+		}
+		for _, arg := range prompt.Arguments {
+			required, known := args[arg.Name]
+			if !known || arg.Required != required || arg.Description == "" {
+				t.Errorf("%s argument %+v, want declared with a description and required=%v", prompt.Name, arg, required)
+			}
+		}
+	}
+}
+
+func TestReportPrompts_ArgumentType(t *testing.T) {
+	t.Parallel()
+
+	rp := newReportPrompts(t, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	tests := []struct {
+		prompt   string
+		argument string
+		expected string
+	}{
+		{prompt: "compare-reports", argument: "report1", expected: prompts.ArgumentTypeReport},
+		{prompt: "monthly-report", argument: "month", expected: prompts.ArgumentTypeMonth},
+		{prompt: "analyze-traffic", argument: "date_range", expected: ""},
+		{prompt: "analyze-traffic", argument: "report1", expected: ""},
+		{prompt: "weekly", argument: "report1", expected: ""},
+	}
+
+	for _, tt := range tests {
+		if got := rp.ArgumentType(tt.prompt, tt.argument); got != tt.expected {
+			t.Errorf("ArgumentType(%q, %q) = %q, want %q", tt.prompt, tt.argument, got, tt.expected)
+		}
+	}
+}
+
 // validateMultipleMessages validates that the prompt contains multiple expected messages.
 func validateMultipleMessages(t *testing.T, result *mcp.GetPromptResult, expectedTexts []string, promptName string) {
 	if len(result.Messages) == 0 {
@@ -216,13 +349,14 @@ func TestRealTimeInsightsPrompt(t *testing.T) {
 	t.Parallel()
 
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
-	rp := prompts.NewReportPrompts(logger)
+	rp := newReportPrompts(t, logger)
 
 	params := &mcp.GetPromptParams{
+		Name:      "realtime-insights",
 		Arguments: map[string]string{},
 	}
 
-	result, err := rp.RealTimeInsightsPrompt(context.Background(), nil, params)
+	result, err := rp.GetPrompt(context.Background(), nil, params)
 	if err != nil {
 		t.Errorf("RealTimeInsightsPrompt() error = %v", err)
 		return
@@ -274,34 +408,32 @@ func TestPromptResultStructure(t *testing.T) {
 	t.Parallel()
 
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
-	rp := prompts.NewReportPrompts(logger)
-
-	// Test all prompt methods to ensure they return properly structured results
-	prompts := []struct {
-		name   string
-		method func(context.Context, *mcp.ServerSession, *mcp.GetPromptParams) (*mcp.GetPromptResult, error)
-	}{
-		{"AnalyzeTrafficPrompt", rp.AnalyzeTrafficPrompt},
-		{"CompareReportsPrompt", rp.CompareReportsPrompt},
-		{"MonthlyReportPrompt", rp.MonthlyReportPrompt},
-		{"RealTimeInsightsPrompt", rp.RealTimeInsightsPrompt},
-	}
+	rp := newReportPrompts(t, logger)
 
-	for _, prompt := range prompts {
-		t.Run(prompt.name, func(t *testing.T) {
+	// Test all prompts to ensure they return properly structured results
+	for _, prompt := range rp.Prompts() {
+		t.Run(prompt.Name, func(t *testing.T) {
 			t.Parallel()
 
+			// Required arguments have no default, so give them a valid value.
+			arguments := map[string]string{}
+			for _, arg := range prompt.Arguments {
+				if arg.Required {
+					arguments[arg.Name] = map[string]string{"month": "03", "year": "2024"}[arg.Name]
+				}
+			}
 			params := &mcp.GetPromptParams{
-				Arguments: map[string]string{},
+				Name:      prompt.Name,
+				Arguments: arguments,
 			}
 
-			result, err := prompt.method(context.Background(), nil, params)
+			result, err := rp.GetPrompt(context.Background(), nil, params)
 			if err != nil {
-				t.Errorf("%s error = %v", prompt.name, err)
+				t.Errorf("%s error = %v", prompt.Name, err)
 				return
 			}
 
-			validatePromptResult(t, result, prompt.name)
+			validatePromptResult(t, result, prompt.Name)
 		})
 	}
 }
@@ -0,0 +1,189 @@
+package prompts
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// templateExt is the file extension of prompt templates.
+const templateExt = ".tmpl"
+
+// frontMatterDelimiter separates the JSON header of a prompt template from its text.
+const frontMatterDelimiter = "---"
+
+// defaultTemplates holds the built-in prompt templates.
+//
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// PromptArgument declares an argument of a prompt template. Arguments with a type, such as
+// ArgumentTypeReport, only accept values valid for that type.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+// PromptTemplate is a prompt rendered from a text/template file. The file starts with a JSON header
+// between "---" lines declaring the description and arguments, followed by the template text, which
+// refers to the arguments by name, e.g. {{.date_range}}.
+type PromptTemplate struct {
+	Name        string           `json:"-"`
+	Description string           `json:"description"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+
+	source string
+	tmpl   *template.Template
+}
+
+// ParsePromptTemplate parses the prompt template with the given name from its file contents.
+func ParsePromptTemplate(name, source string) (*PromptTemplate, error) {
+	header, text, err := splitFrontMatter(source)
+	if err != nil {
+		return nil, fmt.Errorf("prompt template %q: %w", name, err)
+	}
+
+	pt := &PromptTemplate{Name: name, source: source}
+	decoder := json.NewDecoder(strings.NewReader(header))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(pt); err != nil {
+		return nil, fmt.Errorf("prompt template %q: invalid header: %w", name, err)
+	}
+	if pt.Description == "" {
+		return nil, fmt.Errorf("prompt template %q: description is required", name)
+	}
+
+	seen := make(map[string]bool, len(pt.Arguments))
+	for _, arg := range pt.Arguments {
+		if arg.Name == "" || seen[arg.Name] {
+			return nil, fmt.Errorf("prompt template %q: argument names must be unique and not empty", name)
+		}
+		seen[arg.Name] = true
+
+		if _, ok := argumentValidators[arg.Type]; arg.Type != "" && !ok {
+			return nil, fmt.Errorf("prompt template %q: argument %q has unknown type %q", name, arg.Name, arg.Type)
+		}
+	}
+
+	pt.tmpl, err = template.New(name).Option("missingkey=error").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("prompt template %q: %w", name, err)
+	}
+	return pt, nil
+}
+
+// Prompt returns the MCP prompt declaration of the template.
+func (pt *PromptTemplate) Prompt() *mcp.Prompt {
+	args := make([]*mcp.PromptArgument, len(pt.Arguments))
+	for i, arg := range pt.Arguments {
+		description := arg.Description
+		if arg.Default != "" {
+			description = strings.TrimSpace(fmt.Sprintf("%s (default: %s)", description, arg.Default))
+		}
+		args[i] = &mcp.PromptArgument{Name: arg.Name, Description: description, Required: arg.Required}
+	}
+	return &mcp.Prompt{Name: pt.Name, Description: pt.Description, Arguments: args}
+}
+
+// Render validates the arguments, fills in the defaults of the missing ones and executes the template.
+func (pt *PromptTemplate) Render(arguments map[string]string) (string, error) {
+	data := make(map[string]string, len(pt.Arguments))
+	for _, arg := range pt.Arguments {
+		value := strings.TrimSpace(arguments[arg.Name])
+		if value == "" {
+			if arg.Required {
+				return "", fmt.Errorf("missing required argument %q", arg.Name)
+			}
+			value = arg.Default
+		}
+		if validate, ok := argumentValidators[arg.Type]; ok && value != "" {
+			normalized, err := validate(value)
+			if err != nil {
+				return "", fmt.Errorf("invalid argument %q: %w", arg.Name, err)
+			}
+			value = normalized
+		}
+		data[arg.Name] = value
+	}
+	for name := range arguments {
+		if _, ok := data[name]; !ok {
+			return "", fmt.Errorf("unknown argument %q", name)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pt.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt %q: %w", pt.Name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// LoadPromptTemplates loads the built-in prompt templates and the *.tmpl files in dir, if set.
+// A file in dir replaces the built-in template of the same name and any other file adds a prompt.
+func LoadPromptTemplates(dir string) (map[string]*PromptTemplate, error) {
+	templates := make(map[string]*PromptTemplate)
+
+	sub, err := fs.Sub(defaultTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	if err = loadTemplateDir(sub, templates); err != nil {
+		return nil, err
+	}
+
+	if dir != "" {
+		if err = loadTemplateDir(os.DirFS(dir), templates); err != nil {
+			return nil, err
+		}
+	}
+	return templates, nil
+}
+
+// loadTemplateDir parses the *.tmpl files at the top of fsys into templates, keyed by file name
+// without the extension.
+func loadTemplateDir(fsys fs.FS, templates map[string]*PromptTemplate) error {
+	matches, err := fs.Glob(fsys, "*"+templateExt)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range matches {
+		data, readErr := fs.ReadFile(fsys, file)
+		if readErr != nil {
+			return fmt.Errorf("failed to read prompt template: %w", readErr)
+		}
+		name := strings.TrimSuffix(path.Base(file), templateExt)
+		pt, parseErr := ParsePromptTemplate(name, string(data))
+		if parseErr != nil {
+			return parseErr
+		}
+		templates[name] = pt
+	}
+	return nil
+}
+
+// splitFrontMatter splits a template file into its JSON header and its template text.
+func splitFrontMatter(source string) (string, string, error) {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	rest, ok := strings.CutPrefix(source, frontMatterDelimiter+"\n")
+	if !ok {
+		return "", "", errors.New("missing header, the file must start with a --- line")
+	}
+	header, text, ok := strings.Cut(rest, "\n"+frontMatterDelimiter+"\n")
+	if !ok {
+		return "", "", errors.New("header is not terminated by a --- line")
+	}
+	return header, text, nil
+}
@@ -0,0 +1,334 @@
+package prompts_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/prompts"
+)
+
+const weeklyTemplate = `---
+{
+  "description": "Weekly traffic summary",
+  "arguments": [
+    {"name": "agency", "description": "Agency to summarize", "required": true},
+    {"name": "week", "description": "Week to summarize", "default": "last week"}
+  ]
+}
+---
+Summarize {{.week}} of traffic for {{.agency}}.
+`
+
+// fakePromptRegistry records the prompts added to and removed from it.
+type fakePromptRegistry struct {
+	mu      sync.Mutex
+	prompts map[string]*mcp.Prompt
+	changes int
+}
+
+func newFakePromptRegistry() *fakePromptRegistry {
+	return &fakePromptRegistry{prompts: make(map[string]*mcp.Prompt)}
+}
+
+func (r *fakePromptRegistry) AddPrompt(p *mcp.Prompt, _ mcp.PromptHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prompts[p.Name] = p
+	r.changes++
+}
+
+func (r *fakePromptRegistry) RemovePrompts(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		delete(r.prompts, name)
+	}
+	r.changes++
+}
+
+func (r *fakePromptRegistry) get(name string) (*mcp.Prompt, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.prompts[name], r.changes
+}
+
+func writeTemplate(t *testing.T, dir, name, source string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name+".tmpl"), []byte(source), 0o600); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+}
+
+func TestParsePromptTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		errMsg string
+	}{
+		{name: "valid template", source: weeklyTemplate},
+		{name: "missing header", source: "Summarize traffic.", errMsg: "missing header"},
+		{name: "unterminated header", source: "---\n{\"description\": \"x\"}\nSummarize.", errMsg: "not terminated"},
+		{name: "invalid JSON", source: "---\n{description}\n---\nSummarize.", errMsg: "invalid header"},
+		{
+			name:   "unknown header field",
+			source: "---\n{\"description\": \"x\", \"args\": []}\n---\nx",
+			errMsg: "unknown field",
+		},
+		{name: "missing description", source: "---\n{}\n---\nSummarize.", errMsg: "description is required"},
+		{
+			name:   "duplicate argument",
+			source: "---\n{\"description\": \"x\", \"arguments\": [{\"name\": \"a\"}, {\"name\": \"a\"}]}\n---\n{{.a}}",
+			errMsg: "must be unique",
+		},
+		{
+			name:   "unknown argument type",
+			source: "---\n{\"description\": \"x\", \"arguments\": [{\"name\": \"a\", \"type\": \"day\"}]}\n---\n{{.a}}",
+			errMsg: `unknown type "day"`,
+		},
+		{name: "invalid template", source: "---\n{\"description\": \"x\"}\n---\n{{.a", errMsg: "unclosed action"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pt, err := prompts.ParsePromptTemplate("weekly", tt.source)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("ParsePromptTemplate() unexpected error: %v", err)
+				}
+				if pt.Name != "weekly" || len(pt.Arguments) != 2 {
+					t.Errorf("ParsePromptTemplate() = %+v, want weekly with 2 arguments", pt)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("ParsePromptTemplate() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestPromptTemplate_Render(t *testing.T) {
+	t.Parallel()
+
+	pt, err := prompts.ParsePromptTemplate("weekly", weeklyTemplate)
+	if err != nil {
+		t.Fatalf("ParsePromptTemplate() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		arguments map[string]string
+		expected  string
+		errMsg    string
+	}{
+		{
+			name:      "all arguments",
+			arguments: map[string]string{"agency": "NASA", "week": "the first week of May"},
+			expected:  "Summarize the first week of May of traffic for NASA.",
+		},
+		{
+			name:      "default argument",
+			arguments: map[string]string{"agency": "NASA"},
+			expected:  "Summarize last week of traffic for NASA.",
+		},
+		{name: "missing required argument", arguments: map[string]string{}, errMsg: `missing required argument "agency"`},
+		{
+			name:      "unknown argument",
+			arguments: map[string]string{"agency": "NASA", "month": "05"},
+			errMsg:    `unknown argument "month"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			text, renderErr := pt.Render(tt.arguments)
+			if tt.errMsg != "" {
+				if renderErr == nil || !strings.Contains(renderErr.Error(), tt.errMsg) {
+					t.Errorf("Render() error = %v, want error containing %q", renderErr, tt.errMsg)
+				}
+				return
+			}
+			if renderErr != nil {
+				t.Fatalf("Render() unexpected error: %v", renderErr)
+			}
+			if text != tt.expected {
+				t.Errorf("Render() = %q, want %q", text, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPromptTemplate_Prompt(t *testing.T) {
+	t.Parallel()
+
+	pt, err := prompts.ParsePromptTemplate("weekly", weeklyTemplate)
+	if err != nil {
+		t.Fatalf("ParsePromptTemplate() unexpected error: %v", err)
+	}
+
+	prompt := pt.Prompt()
+	if prompt.Name != "weekly" || prompt.Description != "Weekly traffic summary" || len(prompt.Arguments) != 2 {
+		t.Fatalf("Prompt() = %+v, want the weekly prompt with 2 arguments", prompt)
+	}
+	if agency := prompt.Arguments[0]; agency.Name != "agency" || !agency.Required {
+		t.Errorf("Prompt() agency argument = %+v, want required", agency)
+	}
+	if week := prompt.Arguments[1]; week.Required || week.Description != "Week to summarize (default: last week)" {
+		t.Errorf("Prompt() week argument = %+v, want optional with its default described", week)
+	}
+}
+
+func TestLoadPromptTemplates(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTemplate(t, dir, "weekly", weeklyTemplate)
+	writeTemplate(t, dir, "realtime-insights", "---\n{\"description\": \"Custom realtime\"}\n---\nCustom text.")
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("ignored"), 0o600); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+
+	templates, err := prompts.LoadPromptTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadPromptTemplates() unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"analyze-traffic", "compare-reports", "monthly-report", "realtime-insights", "weekly"} {
+		if templates[name] == nil {
+			t.Errorf("LoadPromptTemplates() is missing %q", name)
+		}
+	}
+	if len(templates) != 5 {
+		t.Errorf("LoadPromptTemplates() loaded %d templates, want 5", len(templates))
+	}
+	if got := templates["realtime-insights"].Description; got != "Custom realtime" {
+		t.Errorf("Overridden realtime-insights description = %q, want Custom realtime", got)
+	}
+
+	writeTemplate(t, dir, "broken", "no header")
+	if _, err = prompts.LoadPromptTemplates(dir); err == nil {
+		t.Error("LoadPromptTemplates() with a broken template expected error but got none")
+	}
+}
+
+func TestReportPrompts_Reload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	rp, err := prompts.NewReportPromptsWithDir(logger, dir)
+	if err != nil {
+		t.Fatalf("NewReportPromptsWithDir() unexpected error: %v", err)
+	}
+
+	registry := newFakePromptRegistry()
+	rp.Register(registry)
+	if _, changes := registry.get(""); changes != 4 {
+		t.Fatalf("Register() made %d changes, want the 4 built-in prompts", changes)
+	}
+
+	// Unchanged templates are not registered again.
+	if err = rp.Reload(registry); err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+	if _, changes := registry.get(""); changes != 4 {
+		t.Errorf("Reload() without changes made %d changes, want 4", changes)
+	}
+
+	writeTemplate(t, dir, "weekly", weeklyTemplate)
+	if err = rp.Reload(registry); err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+	if prompt, changes := registry.get("weekly"); prompt == nil || changes != 5 {
+		t.Errorf("Reload() registered %v with %d changes, want weekly added", prompt, changes)
+	}
+
+	writeTemplate(t, dir, "broken", "no header")
+	if err = rp.Reload(registry); err == nil {
+		t.Error("Reload() with a broken template expected error but got none")
+	}
+	result, err := rp.GetPrompt(context.Background(), nil, &mcp.GetPromptParams{
+		Name:      "weekly",
+		Arguments: map[string]string{"agency": "NASA"},
+	})
+	if err != nil {
+		t.Fatalf("GetPrompt() after a failed reload unexpected error: %v", err)
+	}
+	if text := result.Messages[0].Content.(*mcp.TextContent).Text; !strings.Contains(text, "NASA") {
+		t.Errorf("GetPrompt() = %q, want the weekly prompt", text)
+	}
+
+	for _, name := range []string{"weekly", "broken"} {
+		if err = os.Remove(filepath.Join(dir, name+".tmpl")); err != nil {
+			t.Fatalf("Remove() unexpected error: %v", err)
+		}
+	}
+	if err = rp.Reload(registry); err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+	if prompt, _ := registry.get("weekly"); prompt != nil {
+		t.Error("Reload() kept the weekly prompt after its template was removed")
+	}
+}
+
+func TestReportPrompts_WatchTemplates(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	rp, err := prompts.NewReportPromptsWithDir(logger, dir)
+	if err != nil {
+		t.Fatalf("NewReportPromptsWithDir() unexpected error: %v", err)
+	}
+
+	registry := newFakePromptRegistry()
+	rp.Register(registry)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go rp.WatchTemplates(ctx, registry, 10*time.Millisecond)
+
+	writeTemplate(t, dir, "weekly", weeklyTemplate)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if prompt, _ := registry.get("weekly"); prompt != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the weekly prompt to be registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReportPrompts_GetPrompt_Errors(t *testing.T) {
+	t.Parallel()
+
+	rp := newReportPrompts(t, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if _, err := rp.GetPrompt(context.Background(), nil, &mcp.GetPromptParams{Name: "weekly"}); err == nil {
+		t.Error("GetPrompt() of an unknown prompt expected error but got none")
+	}
+
+	_, err := rp.GetPrompt(context.Background(), nil, &mcp.GetPromptParams{
+		Name:      "analyze-traffic",
+		Arguments: map[string]string{"range": "last week"},
+	})
+	if err == nil || !strings.Contains(err.Error(), `unknown argument "range"`) {
+		t.Errorf("GetPrompt() error = %v, want unknown argument error", err)
+	}
+}
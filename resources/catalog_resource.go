@@ -0,0 +1,54 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+// ReportCatalogURI is the URI of the resource listing all report types. The catalog lives apart from
+// dap://reports/{report_name}, which serves report data.
+const ReportCatalogURI = "dap://catalog/reports"
+
+// ReportResourceURI returns the URI of the resource describing a single report type.
+func ReportResourceURI(name models.ReportType) string {
+	return ReportCatalogURI + "/" + string(name)
+}
+
+// HandleReportCatalog serves the report catalog at dap://catalog/reports and the description of
+// a single report type at dap://catalog/reports/{name}, both as JSON.
+func (rh *ResourceHandler) HandleReportCatalog(_ context.Context, _ *mcp.ServerSession,
+	params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+	rh.logger.Info("Processing report catalog request", "uri", params.URI)
+
+	var payload any
+	if params.URI == ReportCatalogURI {
+		payload = models.ReportCatalog()
+	} else {
+		name, ok := strings.CutPrefix(params.URI, ReportCatalogURI+"/")
+		if !ok {
+			return nil, mcp.ResourceNotFoundError(params.URI)
+		}
+		info, ok := models.LookupReport(name)
+		if !ok {
+			rh.logger.Error("Report type not found", "name", name)
+			return nil, mcp.ResourceNotFoundError(params.URI)
+		}
+		payload = info
+	}
+
+	body, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report catalog: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: params.URI, MIMEType: "application/json", Text: string(body)},
+		},
+	}, nil
+}
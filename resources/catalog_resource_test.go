@@ -0,0 +1,78 @@
+package resources_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/models"
+	"github.com/rameshsunkara/go-mcp-example/resources"
+)
+
+func TestResourceHandler_HandleReportCatalog(t *testing.T) {
+	t.Parallel()
+
+	rh := resources.NewResourceHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	result, err := rh.HandleReportCatalog(context.Background(), nil,
+		&mcp.ReadResourceParams{URI: resources.ReportCatalogURI})
+	if err != nil {
+		t.Fatalf("HandleReportCatalog() unexpected error: %v", err)
+	}
+	if result.Contents[0].MIMEType != "application/json" {
+		t.Errorf("MIMEType = %q, want application/json", result.Contents[0].MIMEType)
+	}
+
+	var catalog []models.ReportInfo
+	if err = json.Unmarshal([]byte(result.Contents[0].Text), &catalog); err != nil {
+		t.Fatalf("Catalog is not valid JSON: %v", err)
+	}
+	if len(catalog) != len(models.GetAllReportTypes()) {
+		t.Errorf("Catalog has %d reports, want %d", len(catalog), len(models.GetAllReportTypes()))
+	}
+}
+
+func TestResourceHandler_HandleReportCatalog_Report(t *testing.T) {
+	t.Parallel()
+
+	rh := resources.NewResourceHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name      string
+		uri       string
+		expectErr bool
+	}{
+		{name: "known report", uri: resources.ReportResourceURI(models.ReportTypeDevices), expectErr: false},
+		{name: "unknown report", uri: "dap://catalog/reports/weather", expectErr: true},
+		{name: "report data URI", uri: "dap://reports/devices", expectErr: true},
+		{name: "other URI", uri: "dap://agencies", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := rh.HandleReportCatalog(context.Background(), nil, &mcp.ReadResourceParams{URI: tt.uri})
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("HandleReportCatalog(%q) expected error but got none", tt.uri)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("HandleReportCatalog(%q) unexpected error: %v", tt.uri, err)
+			}
+
+			var info models.ReportInfo
+			if err = json.Unmarshal([]byte(result.Contents[0].Text), &info); err != nil {
+				t.Fatalf("Report is not valid JSON: %v", err)
+			}
+			if info.Name != models.ReportTypeDevices || info.DefaultDimension != "device" {
+				t.Errorf("Report = %+v, want devices with default dimension device", info)
+			}
+		})
+	}
+}
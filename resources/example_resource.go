@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"sync"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -16,12 +17,27 @@ var EmbeddedResources = map[string]string{
 // ResourceHandler handles resource operations.
 type ResourceHandler struct {
 	logger *slog.Logger
+
+	// dir is the resources directory whose files are served as resources, empty if none.
+	dir string
+
+	// files holds the URIs of the registered file resources.
+	mu    sync.Mutex
+	files map[string]bool
 }
 
 // NewResourceHandler creates a new ResourceHandler with the provided logger.
 func NewResourceHandler(logger *slog.Logger) *ResourceHandler {
+	return NewResourceHandlerWithDir(logger, "")
+}
+
+// NewResourceHandlerWithDir creates a new ResourceHandler that also serves the files in the
+// resources directory.
+func NewResourceHandlerWithDir(logger *slog.Logger, dir string) *ResourceHandler {
 	return &ResourceHandler{
 		logger: logger,
+		dir:    dir,
+		files:  make(map[string]bool),
 	}
 }
 
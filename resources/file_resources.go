@@ -0,0 +1,195 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// fileResourceHost is the URI host of the resources read from the resources directory.
+const fileResourceHost = "files"
+
+// fileMIMETypes maps the extensions of the files served from the resources directory to their MIME types.
+// Files with other extensions are ignored.
+var fileMIMETypes = map[string]string{
+	".md":   "text/markdown",
+	".json": "application/json",
+	".csv":  "text/csv",
+}
+
+// ResourceRegistry is the part of the MCP server that file resources are registered with.
+// Adding and removing resources notifies clients that the resource list changed.
+type ResourceRegistry interface {
+	AddResource(r *mcp.Resource, h mcp.ResourceHandler)
+	RemoveResources(uris ...string)
+}
+
+// FileResourceURI returns the URI of a file in the resources directory, given its slash-separated
+// path relative to the directory.
+func FileResourceURI(name string) string {
+	return (&url.URL{Scheme: "dap", Host: fileResourceHost, Path: "/" + name}).String()
+}
+
+// FileMIMEType returns the MIME type of a resource file, and false if the file type is not served.
+func FileMIMEType(name string) (string, bool) {
+	mimeType, ok := fileMIMETypes[strings.ToLower(path.Ext(name))]
+	return mimeType, ok
+}
+
+// FileResources lists the Markdown, JSON and CSV files in the resources directory as resources.
+// Hidden files and directories are skipped.
+func (rh *ResourceHandler) FileResources() ([]*mcp.Resource, error) {
+	var list []*mcp.Resource
+	err := filepath.WalkDir(rh.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != rh.dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(rh.dir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		mimeType, ok := FileMIMEType(name)
+		if !ok {
+			return nil
+		}
+		list = append(list, &mcp.Resource{
+			Name:     "file-" + name,
+			MIMEType: mimeType,
+			URI:      FileResourceURI(name),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan resources directory: %w", err)
+	}
+	return list, nil
+}
+
+// HandleFileResource reads a file from the resources directory. Paths that would leave the
+// directory, including through symbolic links, are rejected.
+func (rh *ResourceHandler) HandleFileResource(ctx context.Context, _ *mcp.ServerSession,
+	params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+	rh.logger.InfoContext(ctx, "Processing file resource request", "uri", params.URI)
+
+	u, err := url.Parse(params.URI)
+	if err != nil || u.Scheme != "dap" || u.Host != fileResourceHost {
+		return nil, mcp.ResourceNotFoundError(params.URI)
+	}
+	name := strings.TrimPrefix(u.Path, "/")
+	mimeType, ok := FileMIMEType(name)
+	if !ok || !fs.ValidPath(name) {
+		rh.logger.ErrorContext(ctx, "Invalid file resource path", "path", name)
+		return nil, mcp.ResourceNotFoundError(params.URI)
+	}
+
+	root, err := os.OpenRoot(rh.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open resources directory: %w", err)
+	}
+	defer root.Close()
+
+	data, err := readRootFile(root, name)
+	if err != nil {
+		rh.logger.ErrorContext(ctx, "Failed to read file resource", "path", name, "error", err)
+		return nil, mcp.ResourceNotFoundError(params.URI)
+	}
+
+	rh.logger.InfoContext(ctx, "File resource retrieved successfully", "path", name, "length", len(data))
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: params.URI, MIMEType: mimeType, Text: string(data)},
+		},
+	}, nil
+}
+
+// SyncFileResources registers the files currently in the resources directory and removes the
+// resources of files that were deleted since the last call.
+func (rh *ResourceHandler) SyncFileResources(registry ResourceRegistry) error {
+	list, err := rh.FileResources()
+	if err != nil {
+		return err
+	}
+
+	rh.mu.Lock()
+	defer rh.mu.Unlock()
+
+	current := make(map[string]bool, len(list))
+	for _, resource := range list {
+		current[resource.URI] = true
+		if !rh.files[resource.URI] {
+			rh.logger.Info("Registering file resource", "uri", resource.URI)
+			registry.AddResource(resource, rh.HandleFileResource)
+		}
+	}
+
+	var removed []string
+	for uri := range rh.files {
+		if !current[uri] {
+			removed = append(removed, uri)
+		}
+	}
+	if len(removed) > 0 {
+		rh.logger.Info("Removing file resources", "uris", removed)
+		registry.RemoveResources(removed...)
+	}
+
+	rh.files = current
+	return nil
+}
+
+// WatchFileResources rescans the resources directory every interval until the context is
+// canceled, so that added and removed files show up in the resource list.
+func (rh *ResourceHandler) WatchFileResources(ctx context.Context, registry ResourceRegistry, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := rh.SyncFileResources(registry); err != nil {
+				rh.logger.WarnContext(ctx, "Failed to rescan resources directory", "error", err)
+			}
+		}
+	}
+}
+
+// readRootFile reads a regular file inside root.
+func readRootFile(root *os.Root, name string) ([]byte, error) {
+	f, err := root.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, errors.New("not a regular file")
+	}
+	return io.ReadAll(f)
+}
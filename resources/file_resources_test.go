@@ -0,0 +1,209 @@
+package resources_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/resources"
+)
+
+// writeResourceFiles creates the given files, keyed by slash-separated path, in dir.
+func writeResourceFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll() unexpected error: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() unexpected error: %v", err)
+		}
+	}
+}
+
+func newFileResourceHandler(t *testing.T, files map[string]string) (*resources.ResourceHandler, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeResourceFiles(t, dir, files)
+	return resources.NewResourceHandlerWithDir(slog.New(slog.NewTextHandler(io.Discard, nil)), dir), dir
+}
+
+func TestResourceHandler_FileResources(t *testing.T) {
+	t.Parallel()
+
+	rh, _ := newFileResourceHandler(t, map[string]string{
+		"glossary.md":         "# Glossary",
+		"goals/targets.json":  `{"visits": 1000}`,
+		"baselines/2024.CSV":  "date,visits\n",
+		"notes.txt":           "ignored",
+		".hidden.md":          "ignored",
+		".drafts/internal.md": "ignored",
+	})
+
+	list, err := rh.FileResources()
+	if err != nil {
+		t.Fatalf("FileResources() unexpected error: %v", err)
+	}
+
+	got := make(map[string]string, len(list))
+	for _, resource := range list {
+		got[resource.URI] = resource.MIMEType
+	}
+	want := map[string]string{
+		"dap://files/glossary.md":        "text/markdown",
+		"dap://files/goals/targets.json": "application/json",
+		"dap://files/baselines/2024.CSV": "text/csv",
+	}
+	if len(got) != len(want) {
+		t.Errorf("FileResources() = %v, want %v", got, want)
+	}
+	for uri, mimeType := range want {
+		if got[uri] != mimeType {
+			t.Errorf("FileResources()[%s] MIME type = %q, want %q", uri, got[uri], mimeType)
+		}
+	}
+}
+
+func TestResourceHandler_HandleFileResource(t *testing.T) {
+	t.Parallel()
+
+	rh, dir := newFileResourceHandler(t, map[string]string{
+		"glossary.md":        "# Glossary",
+		"goals/targets.json": `{"visits": 1000}`,
+		"notes.txt":          "not served",
+	})
+
+	outside := filepath.Join(t.TempDir(), "secret.md")
+	writeResourceFiles(t, filepath.Dir(outside), map[string]string{"secret.md": "secret"})
+	if err := os.Symlink(outside, filepath.Join(dir, "link.md")); err != nil {
+		t.Fatalf("Symlink() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		uri          string
+		expectedText string
+		expectedMIME string
+		expectErr    bool
+	}{
+		{name: "markdown file", uri: "dap://files/glossary.md", expectedText: "# Glossary", expectedMIME: "text/markdown"},
+		{
+			name:         "nested JSON file",
+			uri:          "dap://files/goals/targets.json",
+			expectedText: `{"visits": 1000}`,
+			expectedMIME: "application/json",
+		},
+		{name: "unsupported extension", uri: "dap://files/notes.txt", expectErr: true},
+		{name: "missing file", uri: "dap://files/missing.md", expectErr: true},
+		{name: "parent directory", uri: "dap://files/../secret.md", expectErr: true},
+		{name: "encoded parent directory", uri: "dap://files/goals/%2E%2E/%2E%2E/secret.md", expectErr: true},
+		{name: "symlink leaving the directory", uri: "dap://files/link.md", expectErr: true},
+		{name: "other host", uri: "dap://reports/glossary.md", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := rh.HandleFileResource(context.Background(), nil, &mcp.ReadResourceParams{URI: tt.uri})
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("HandleFileResource(%q) expected error but got none", tt.uri)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("HandleFileResource(%q) unexpected error: %v", tt.uri, err)
+			}
+			contents := result.Contents[0]
+			if contents.Text != tt.expectedText || contents.MIMEType != tt.expectedMIME {
+				t.Errorf("Contents = %q (%s), want %q (%s)",
+					contents.Text, contents.MIMEType, tt.expectedText, tt.expectedMIME)
+			}
+		})
+	}
+}
+
+func TestResourceHandler_SyncFileResources(t *testing.T) {
+	t.Parallel()
+
+	rh, dir := newFileResourceHandler(t, map[string]string{"glossary.md": "# Glossary"})
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+
+	ctx := context.Background()
+	listChanged := make(chan struct{}, 10)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, &mcp.ClientOptions{
+		ResourceListChangedHandler: func(context.Context, *mcp.ClientSession, *mcp.ResourceListChangedParams) {
+			listChanged <- struct{}{}
+		},
+	})
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport); err != nil {
+		t.Fatalf("Server Connect() unexpected error: %v", err)
+	}
+	session, err := client.Connect(ctx, clientTransport)
+	if err != nil {
+		t.Fatalf("Client Connect() unexpected error: %v", err)
+	}
+	defer session.Close()
+
+	listURIs := func() []string {
+		t.Helper()
+		result, listErr := session.ListResources(ctx, nil)
+		if listErr != nil {
+			t.Fatalf("ListResources() unexpected error: %v", listErr)
+		}
+		var uris []string
+		for _, resource := range result.Resources {
+			uris = append(uris, resource.URI)
+		}
+		slices.Sort(uris)
+		return uris
+	}
+	waitListChanged := func() {
+		t.Helper()
+		select {
+		case <-listChanged:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for a resource list changed notification")
+		}
+	}
+
+	if err = rh.SyncFileResources(server); err != nil {
+		t.Fatalf("SyncFileResources() unexpected error: %v", err)
+	}
+	waitListChanged()
+	if got := listURIs(); !slices.Equal(got, []string{"dap://files/glossary.md"}) {
+		t.Errorf("Resources = %v, want the glossary", got)
+	}
+
+	writeResourceFiles(t, dir, map[string]string{"targets.csv": "date,visits\n"})
+	if err = os.Remove(filepath.Join(dir, "glossary.md")); err != nil {
+		t.Fatalf("Remove() unexpected error: %v", err)
+	}
+	if err = rh.SyncFileResources(server); err != nil {
+		t.Fatalf("SyncFileResources() unexpected error: %v", err)
+	}
+	waitListChanged()
+	if got := listURIs(); !slices.Equal(got, []string{"dap://files/targets.csv"}) {
+		t.Errorf("Resources = %v, want only the targets", got)
+	}
+
+	result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "dap://files/targets.csv"})
+	if err != nil {
+		t.Fatalf("ReadResource() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(result.Contents[0].Text, "date,visits") {
+		t.Errorf("Contents = %q, want the CSV file", result.Contents[0].Text)
+	}
+}
@@ -0,0 +1,248 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// JSON-RPC methods handled by Subscriptions.
+const (
+	methodInitialize            = "initialize"
+	methodSubscribe             = "resources/subscribe"
+	methodUnsubscribe           = "resources/unsubscribe"
+	notificationResourceUpdated = "notifications/resources/updated"
+)
+
+// Subscriptions tracks which client connections subscribed to which resources and sends them
+// resource-updated notifications.
+//
+// The MCP SDK neither routes resources/subscribe and resources/unsubscribe requests to the server
+// nor sends resource-updated notifications, so Subscriptions handles both on the connection:
+// wrap the server transport with Transport to answer the requests and to advertise the subscribe
+// capability when the client initializes. Over HTTP, StreamableHTTPHandler wraps the transport of
+// each session.
+type Subscriptions struct {
+	logger       *slog.Logger
+	subscribable map[string]bool
+
+	mu    sync.Mutex
+	conns map[*subscriptionConn]map[string]bool
+}
+
+// NewSubscriptions creates a Subscriptions that allows clients to subscribe to the given URIs.
+func NewSubscriptions(logger *slog.Logger, uris ...string) *Subscriptions {
+	subscribable := make(map[string]bool, len(uris))
+	for _, uri := range uris {
+		subscribable[uri] = true
+	}
+	return &Subscriptions{
+		logger:       logger,
+		subscribable: subscribable,
+		conns:        make(map[*subscriptionConn]map[string]bool),
+	}
+}
+
+// Transport wraps a server transport so that its connections support resource subscriptions.
+func (s *Subscriptions) Transport(t mcp.Transport) mcp.Transport {
+	return &subscriptionTransport{Transport: t, subscriptions: s}
+}
+
+// Subscribed reports whether any connection is subscribed to the resource.
+func (s *Subscriptions) Subscribed(uri string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, uris := range s.conns {
+		if uris[uri] {
+			return true
+		}
+	}
+	return false
+}
+
+// NotifyUpdated sends a resource-updated notification to every connection subscribed to the
+// resource. Delivery failures are logged and do not affect other connections.
+func (s *Subscriptions) NotifyUpdated(ctx context.Context, uri string) {
+	params, err := json.Marshal(map[string]string{"uri": uri})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to marshal resource update", "uri", uri, "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	var subscribers []*subscriptionConn
+	for conn, uris := range s.conns {
+		if uris[uri] {
+			subscribers = append(subscribers, conn)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, conn := range subscribers {
+		notification := &jsonrpc.Request{Method: notificationResourceUpdated, Params: params}
+		if err = conn.Write(ctx, notification); err != nil {
+			s.logger.WarnContext(ctx, "Failed to send resource update", "uri", uri, "error", err)
+		}
+	}
+	s.logger.InfoContext(ctx, "Sent resource updates", "uri", uri, "subscribers", len(subscribers))
+}
+
+// subscribe records a subscription of the connection to the resource.
+func (s *Subscriptions) subscribe(conn *subscriptionConn, uri string) error {
+	if !s.subscribable[uri] {
+		return mcp.ResourceNotFoundError(uri)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conns[conn] == nil {
+		s.conns[conn] = make(map[string]bool)
+	}
+	s.conns[conn][uri] = true
+	return nil
+}
+
+// unsubscribe removes a subscription of the connection. Unknown subscriptions are ignored.
+func (s *Subscriptions) unsubscribe(conn *subscriptionConn, uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns[conn], uri)
+}
+
+// remove drops all subscriptions of a closed connection.
+func (s *Subscriptions) remove(conn *subscriptionConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+}
+
+// subscriptionTransport is a server transport whose connections support resource subscriptions.
+type subscriptionTransport struct {
+	mcp.Transport
+	subscriptions *Subscriptions
+}
+
+// Connect connects the wrapped transport.
+func (t *subscriptionTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	conn, err := t.Transport.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &subscriptionConn{Connection: conn, subscriptions: t.subscriptions}, nil
+}
+
+// subscriptionConn answers subscription requests itself and passes all other messages through.
+type subscriptionConn struct {
+	mcp.Connection
+	subscriptions *Subscriptions
+
+	// writeMu serializes writes of the server with subscription replies and notifications.
+	writeMu sync.Mutex
+
+	mu           sync.Mutex
+	initializeID jsonrpc.ID
+}
+
+// Read returns the next message for the server, answering subscription requests on the way.
+func (c *subscriptionConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	for {
+		msg, err := c.Connection.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		req, ok := msg.(*jsonrpc.Request)
+		if !ok {
+			return msg, nil
+		}
+
+		switch req.Method {
+		case methodSubscribe, methodUnsubscribe:
+			if err = c.Write(ctx, c.handleSubscription(req)); err != nil {
+				return nil, err
+			}
+		case methodInitialize:
+			c.mu.Lock()
+			c.initializeID = req.ID
+			c.mu.Unlock()
+			return msg, nil
+		default:
+			return msg, nil
+		}
+	}
+}
+
+// Write sends a message to the client. The reply to the initialize request is amended to
+// advertise the subscribe capability.
+func (c *subscriptionConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	if resp, ok := msg.(*jsonrpc.Response); ok && resp.Error == nil && c.isInitializeReply(resp) {
+		result, err := advertiseSubscribe(resp.Result)
+		if err != nil {
+			return fmt.Errorf("failed to advertise resource subscriptions: %w", err)
+		}
+		msg = &jsonrpc.Response{ID: resp.ID, Result: result}
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.Connection.Write(ctx, msg)
+}
+
+// Close drops the subscriptions of the connection and closes it.
+func (c *subscriptionConn) Close() error {
+	c.subscriptions.remove(c)
+	return c.Connection.Close()
+}
+
+// handleSubscription applies a subscribe or unsubscribe request and returns the reply.
+func (c *subscriptionConn) handleSubscription(req *jsonrpc.Request) *jsonrpc.Response {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		return &jsonrpc.Response{ID: req.ID, Error: fmt.Errorf("%s requires a resource uri", req.Method)}
+	}
+
+	if req.Method == methodUnsubscribe {
+		c.subscriptions.unsubscribe(c, params.URI)
+	} else if err := c.subscriptions.subscribe(c, params.URI); err != nil {
+		return &jsonrpc.Response{ID: req.ID, Error: err}
+	}
+
+	c.subscriptions.logger.Info("Processed resource subscription", "method", req.Method, "uri", params.URI)
+	return &jsonrpc.Response{ID: req.ID, Result: json.RawMessage("{}")}
+}
+
+// isInitializeReply reports whether the response answers the initialize request.
+func (c *subscriptionConn) isInitializeReply(resp *jsonrpc.Response) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.initializeID.IsValid() && resp.ID == c.initializeID
+}
+
+// advertiseSubscribe sets capabilities.resources.subscribe in an initialize result.
+func advertiseSubscribe(result json.RawMessage) (json.RawMessage, error) {
+	var initialize map[string]any
+	if err := json.Unmarshal(result, &initialize); err != nil {
+		return nil, err
+	}
+
+	capabilities, _ := initialize["capabilities"].(map[string]any)
+	if capabilities == nil {
+		capabilities = make(map[string]any)
+		initialize["capabilities"] = capabilities
+	}
+	resources, _ := capabilities["resources"].(map[string]any)
+	if resources == nil {
+		resources = make(map[string]any)
+		capabilities["resources"] = resources
+	}
+	resources["subscribe"] = true
+
+	return json.Marshal(initialize)
+}
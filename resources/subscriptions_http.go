@@ -0,0 +1,104 @@
+package resources
+
+import (
+	"crypto/rand"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// sessionIDHeader is the header carrying the MCP session ID of the streamable HTTP transport.
+const sessionIDHeader = "Mcp-Session-Id"
+
+// StreamableHTTPHandler serves streamable MCP sessions over HTTP whose connections support resource
+// subscriptions.
+//
+// It follows mcp.StreamableHTTPHandler, which creates the transport of each session internally and so
+// leaves no place to wrap it with Subscriptions.Transport.
+type StreamableHTTPHandler struct {
+	server        *mcp.Server
+	subscriptions *Subscriptions
+
+	mu       sync.Mutex
+	sessions map[string]*mcp.StreamableServerTransport // keyed by MCP session ID
+}
+
+// StreamableHTTPHandler returns a handler serving the server over the streamable HTTP transport, with
+// resource subscriptions.
+func (s *Subscriptions) StreamableHTTPHandler(server *mcp.Server) *StreamableHTTPHandler {
+	return &StreamableHTTPHandler{
+		server:        server,
+		subscriptions: s,
+		sessions:      make(map[string]*mcp.StreamableServerTransport),
+	}
+}
+
+// ServeHTTP handles a request of a new or an existing session. DELETE requests end the session.
+func (h *StreamableHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !acceptsStreamable(r) {
+		http.Error(w, "Accept must contain both 'application/json' and 'text/event-stream'", http.StatusBadRequest)
+		return
+	}
+
+	var session *mcp.StreamableServerTransport
+	if id := r.Header.Get(sessionIDHeader); id != "" {
+		h.mu.Lock()
+		session = h.sessions[id]
+		h.mu.Unlock()
+		if session == nil {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	switch r.Method {
+	case http.MethodDelete:
+		if session == nil {
+			http.Error(w, "DELETE requires an Mcp-Session-Id header", http.StatusBadRequest)
+			return
+		}
+		h.mu.Lock()
+		delete(h.sessions, session.SessionID())
+		h.mu.Unlock()
+		_ = session.Close()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case http.MethodGet, http.MethodPost:
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "unsupported method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if session == nil {
+		session = mcp.NewStreamableServerTransport(rand.Text())
+		// The request context carries the values of the HTTP middleware; the SDK detaches it for
+		// the lifetime of the session.
+		if _, err := h.server.Connect(r.Context(), h.subscriptions.Transport(session)); err != nil {
+			http.Error(w, "failed connection", http.StatusInternalServerError)
+			return
+		}
+		h.mu.Lock()
+		h.sessions[session.SessionID()] = session
+		h.mu.Unlock()
+	}
+
+	session.ServeHTTP(w, r)
+}
+
+// acceptsStreamable reports whether the client accepts the responses of the request: event streams
+// for GET requests, and both JSON and event streams otherwise.
+func acceptsStreamable(r *http.Request) bool {
+	var jsonOK, streamOK bool
+	for _, mediaType := range strings.Split(strings.Join(r.Header.Values("Accept"), ","), ",") {
+		switch strings.TrimSpace(mediaType) {
+		case "application/json":
+			jsonOK = true
+		case "text/event-stream":
+			streamOK = true
+		}
+	}
+	return streamOK && (jsonOK || r.Method == http.MethodGet)
+}
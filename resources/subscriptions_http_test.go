@@ -0,0 +1,162 @@
+package resources_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/resources"
+)
+
+// readUpdate opens the event stream of the session and returns the first resource-updated
+// notification on it.
+func readUpdate(t *testing.T, url, sessionID string) string {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() unexpected error: %v", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok &&
+			strings.Contains(data, "notifications/resources/updated") {
+			return data
+		}
+	}
+	t.Fatalf("Event stream ended without a resource update: %v", scanner.Err())
+	return ""
+}
+
+func TestSubscriptions_StreamableHTTPHandler(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	subscriptions := resources.NewSubscriptions(slog.New(slog.NewTextHandler(io.Discard, nil)), testResourceURI)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	httpServer := httptest.NewServer(subscriptions.StreamableHTTPHandler(server))
+	defer httpServer.Close()
+
+	transport := &subscriptionClientTransport{Transport: mcp.NewStreamableClientTransport(httpServer.URL, nil)}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, transport)
+	if err != nil {
+		t.Fatalf("Client Connect() unexpected error: %v", err)
+	}
+	defer session.Close()
+
+	var initialize struct {
+		Capabilities struct {
+			Resources struct {
+				Subscribe bool `json:"subscribe"`
+			} `json:"resources"`
+		} `json:"capabilities"`
+	}
+	err = json.Unmarshal(transport.initialize, &initialize)
+	if err != nil || !initialize.Capabilities.Resources.Subscribe {
+		t.Errorf("Initialize result = %s, want resources.subscribe capability", transport.initialize)
+	}
+
+	if err = transport.send(ctx, session, "resources/subscribe", testResourceURI); err != nil {
+		t.Fatalf("Subscribe unexpected error: %v", err)
+	}
+	if !subscriptions.Subscribed(testResourceURI) {
+		t.Fatal("Subscribed() = false after subscribing over HTTP")
+	}
+
+	subscriptions.NotifyUpdated(ctx, testResourceURI)
+	if update := readUpdate(t, httpServer.URL, session.ID()); !strings.Contains(update, testResourceURI) {
+		t.Errorf("Update = %s, want an update of %s", update, testResourceURI)
+	}
+
+	var serverSession *mcp.ServerSession
+	for ss := range server.Sessions() {
+		serverSession = ss
+	}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodDelete, httpServer.URL, nil)
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("Mcp-Session-Id", session.ID())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("DELETE status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+
+	_ = serverSession.Wait()
+	if subscriptions.Subscribed(testResourceURI) {
+		t.Error("Subscribed() = true after the session ended")
+	}
+}
+
+func TestSubscriptions_StreamableHTTPHandler_Errors(t *testing.T) {
+	t.Parallel()
+
+	subscriptions := resources.NewSubscriptions(slog.New(slog.NewTextHandler(io.Discard, nil)), testResourceURI)
+	handler := subscriptions.StreamableHTTPHandler(mcp.NewServer(&mcp.Implementation{Name: "test"}, nil))
+
+	tests := []struct {
+		name      string
+		method    string
+		accept    string
+		sessionID string
+		status    int
+	}{
+		{name: "missing accept", method: http.MethodPost, accept: "application/json", status: http.StatusBadRequest},
+		{
+			name:      "unknown session",
+			method:    http.MethodPost,
+			accept:    "application/json, text/event-stream",
+			sessionID: "unknown",
+			status:    http.StatusNotFound,
+		},
+		{
+			name:   "delete without session",
+			method: http.MethodDelete,
+			accept: "application/json, text/event-stream",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unsupported method",
+			method: http.MethodPut,
+			accept: "application/json, text/event-stream",
+			status: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tt.method, "/", strings.NewReader("{}"))
+			req.Header.Set("Accept", tt.accept)
+			if tt.sessionID != "" {
+				req.Header.Set("Mcp-Session-Id", tt.sessionID)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+		})
+	}
+}
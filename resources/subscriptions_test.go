@@ -0,0 +1,199 @@
+package resources_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/resources"
+)
+
+const testResourceURI = "dap://reports/realtime"
+
+// subscriptionClientTransport is a client transport whose connection turns ping requests into
+// subscription requests, since the MCP client cannot send them, and records the messages
+// the client does not handle.
+type subscriptionClientTransport struct {
+	mcp.Transport
+
+	mu            sync.Mutex
+	method        string
+	uri           string
+	initialize    json.RawMessage
+	notifications []string
+}
+
+func (t *subscriptionClientTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	conn, err := t.Transport.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &subscriptionClientConn{Connection: conn, transport: t}, nil
+}
+
+// send makes the next ping request a subscription request with the given method.
+func (t *subscriptionClientTransport) send(ctx context.Context, session *mcp.ClientSession, method, uri string) error {
+	t.mu.Lock()
+	t.method, t.uri = method, uri
+	t.mu.Unlock()
+	return session.Ping(ctx, nil)
+}
+
+func (t *subscriptionClientTransport) updates() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.notifications...)
+}
+
+type subscriptionClientConn struct {
+	mcp.Connection
+	transport *subscriptionClientTransport
+}
+
+func (c *subscriptionClientConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	for {
+		msg, err := c.Connection.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		t := c.transport
+		switch msg := msg.(type) {
+		case *jsonrpc.Request:
+			if msg.Method == "notifications/resources/updated" {
+				t.mu.Lock()
+				t.notifications = append(t.notifications, string(msg.Params))
+				t.mu.Unlock()
+				continue
+			}
+		case *jsonrpc.Response:
+			t.mu.Lock()
+			if t.initialize == nil {
+				t.initialize = msg.Result
+			}
+			t.mu.Unlock()
+		}
+		return msg, nil
+	}
+}
+
+func (c *subscriptionClientConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	if req, ok := msg.(*jsonrpc.Request); ok && req.Method == "ping" {
+		t := c.transport
+		t.mu.Lock()
+		params, err := json.Marshal(map[string]string{"uri": t.uri})
+		msg = &jsonrpc.Request{ID: req.ID, Method: t.method, Params: params}
+		t.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return c.Connection.Write(ctx, msg)
+}
+
+// connectSubscriptionClient connects a client to a server whose transport supports subscriptions.
+func connectSubscriptionClient(t *testing.T, subscriptions *resources.Subscriptions) (
+	*mcp.ClientSession, *mcp.ServerSession, *subscriptionClientTransport) {
+	t.Helper()
+
+	ctx := context.Background()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	serverSession, err := server.Connect(ctx, subscriptions.Transport(serverTransport))
+	if err != nil {
+		t.Fatalf("Server Connect() unexpected error: %v", err)
+	}
+
+	transport := &subscriptionClientTransport{Transport: clientTransport}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, transport)
+	if err != nil {
+		t.Fatalf("Client Connect() unexpected error: %v", err)
+	}
+	return session, serverSession, transport
+}
+
+func TestSubscriptions_AdvertisesCapability(t *testing.T) {
+	t.Parallel()
+
+	subscriptions := resources.NewSubscriptions(slog.New(slog.NewTextHandler(io.Discard, nil)), testResourceURI)
+	session, _, transport := connectSubscriptionClient(t, subscriptions)
+	defer session.Close()
+
+	var initialize struct {
+		ServerInfo   *mcp.Implementation `json:"serverInfo"`
+		Capabilities struct {
+			Resources struct {
+				Subscribe bool `json:"subscribe"`
+			} `json:"resources"`
+		} `json:"capabilities"`
+	}
+	if err := json.Unmarshal(transport.initialize, &initialize); err != nil {
+		t.Fatalf("Initialize result is not valid JSON: %v", err)
+	}
+	if !initialize.Capabilities.Resources.Subscribe {
+		t.Errorf("Initialize result = %s, want resources.subscribe capability", transport.initialize)
+	}
+	if initialize.ServerInfo == nil || initialize.ServerInfo.Name != "test" {
+		t.Errorf("Initialize result = %s, want server info preserved", transport.initialize)
+	}
+}
+
+func TestSubscriptions_SubscribeAndNotify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	subscriptions := resources.NewSubscriptions(slog.New(slog.NewTextHandler(io.Discard, nil)), testResourceURI)
+	subscriber, serverSession, subscriberTransport := connectSubscriptionClient(t, subscriptions)
+	other, _, otherTransport := connectSubscriptionClient(t, subscriptions)
+	defer other.Close()
+
+	if err := subscriberTransport.send(ctx, subscriber, "resources/subscribe", "dap://reports/devices"); err == nil {
+		t.Error("Subscribing to a resource without updates expected error but got none")
+	}
+	if subscriptions.Subscribed(testResourceURI) {
+		t.Fatal("Subscribed() = true before any subscription")
+	}
+
+	if err := subscriberTransport.send(ctx, subscriber, "resources/subscribe", testResourceURI); err != nil {
+		t.Fatalf("Subscribe unexpected error: %v", err)
+	}
+	if !subscriptions.Subscribed(testResourceURI) {
+		t.Fatal("Subscribed() = false after subscribing")
+	}
+
+	subscriptions.NotifyUpdated(ctx, testResourceURI)
+	// Subscribing again is a round trip, after which the notification written before has been read.
+	if err := subscriberTransport.send(ctx, subscriber, "resources/subscribe", testResourceURI); err != nil {
+		t.Fatalf("Repeated subscribe unexpected error: %v", err)
+	}
+	if got := subscriberTransport.updates(); len(got) != 1 || got[0] != `{"uri":"dap://reports/realtime"}` {
+		t.Errorf("Subscriber updates = %v, want one update of %s", got, testResourceURI)
+	}
+	if err := otherTransport.send(ctx, other, "resources/unsubscribe", testResourceURI); err != nil {
+		t.Fatalf("Unsubscribe without subscription unexpected error: %v", err)
+	}
+	if got := otherTransport.updates(); len(got) != 0 {
+		t.Errorf("Other client updates = %v, want none", got)
+	}
+
+	if err := subscriberTransport.send(ctx, subscriber, "resources/unsubscribe", testResourceURI); err != nil {
+		t.Fatalf("Unsubscribe unexpected error: %v", err)
+	}
+	if subscriptions.Subscribed(testResourceURI) {
+		t.Error("Subscribed() = true after unsubscribing")
+	}
+
+	if err := subscriberTransport.send(ctx, subscriber, "resources/subscribe", testResourceURI); err != nil {
+		t.Fatalf("Subscribe unexpected error: %v", err)
+	}
+	subscriber.Close()
+	_ = serverSession.Wait()
+	if subscriptions.Subscribed(testResourceURI) {
+		t.Error("Subscribed() = true after the subscriber disconnected")
+	}
+}
@@ -0,0 +1,134 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+// defaultAggregateMetric is the metric summarized when the caller does not name any.
+const defaultAggregateMetric = "visits"
+
+// AggregateReport implements the aggregate_report tool.
+// It fetches all pages of a report up to the record budget and returns one summary row per group.
+func (rt *ReportsTool) AggregateReport(ctx context.Context, _ *mcp.ServerSession,
+	params *mcp.CallToolParamsFor[models.AggregateArgs]) (*mcp.CallToolResultFor[models.AggregateResponse], error) {
+	args := params.Arguments
+
+	rt.logger.InfoContext(ctx, "Processing aggregate_report tool call",
+		"report_name", args.ReportName,
+		"group_by", args.GroupBy,
+		"metrics", args.Metrics,
+		"functions", args.Functions)
+
+	aggregation := models.Aggregation{
+		GroupBy: args.GroupBy,
+		Metrics: args.Metrics,
+	}
+	for _, function := range args.Functions {
+		aggregation.Functions = append(aggregation.Functions, models.AggregateFunc(function))
+	}
+
+	// Set defaults if not provided
+	if len(aggregation.Metrics) == 0 {
+		aggregation.Metrics = []string{defaultAggregateMetric}
+	}
+	if len(aggregation.Functions) == 0 {
+		aggregation.Functions = []models.AggregateFunc{models.AggregateSum}
+	}
+
+	if err := aggregation.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	request, err := rt.prepareRequest(models.ReportArgs{
+		ReportName: args.ReportName,
+		After:      args.After,
+		Before:     args.Before,
+		DateRange:  args.DateRange,
+		AgencyName: args.AgencyName,
+		Domain:     args.Domain,
+		MaxRecords: args.MaxRecords,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	reports, pagination, err := rt.fetch(ctx, request, true, args.MaxRecords)
+	if err != nil {
+		result := aggregateErrorResult("Request failed: " + err.Error())
+		return result, nil //nolint:nilerr // MCP tools return nil error when IsError is true
+	}
+
+	if len(reports) == 0 {
+		return aggregateErrorResult("No data found for report: " + args.ReportName), nil
+	}
+
+	response := models.AggregateResponse{
+		ReportName: args.ReportName,
+		GroupBy:    aggregation.GroupBy,
+		Columns:    aggregation.Columns(),
+		Rows:       aggregation.Apply(reports),
+		Records:    len(reports),
+		Truncated:  pagination.Truncated,
+	}
+
+	summary := fmt.Sprintf("%d groups from %d records", len(response.Rows), response.Records)
+	if response.Truncated {
+		summary += " (truncated at max_records; more data is available)"
+	}
+
+	text := fmt.Sprintf("Aggregated Report: %s by %s\n\n%s:\n\n%s",
+		args.ReportName, aggregation.GroupBy, summary, formatAggregateTable(response))
+	if warning := rt.quotaWarning(); warning != "" {
+		text += "\n\n" + warning
+	}
+
+	return &mcp.CallToolResultFor[models.AggregateResponse]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+		StructuredContent: response,
+	}, nil
+}
+
+// aggregateErrorResult creates an error tool result whose structured content carries the message.
+func aggregateErrorResult(message string) *mcp.CallToolResultFor[models.AggregateResponse] {
+	return &mcp.CallToolResultFor[models.AggregateResponse]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: message},
+		},
+		StructuredContent: models.AggregateResponse{
+			Columns: []string{},
+			Rows:    []models.AggregateRow{},
+			Error:   &models.Error{Message: message},
+		},
+		IsError: true,
+	}
+}
+
+// formatAggregateTable renders the aggregated rows as a compact Markdown table.
+func formatAggregateTable(response models.AggregateResponse) string {
+	header := append([]string{response.GroupBy, "count"}, response.Columns...)
+	rows := make([][]string, len(response.Rows))
+	for i, row := range response.Rows {
+		group := row.Group
+		if group == "" {
+			group = "(none)"
+		}
+		rows[i] = []string{group, strconv.Itoa(row.Count)}
+		for _, column := range response.Columns {
+			rows[i] = append(rows[i], formatNumber(row.Values[column]))
+		}
+	}
+	return markdownTable(header, rows)
+}
+
+// formatNumber formats a value with at most two decimal places.
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
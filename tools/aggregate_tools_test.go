@@ -0,0 +1,190 @@
+package tools_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/models"
+	"github.com/rameshsunkara/go-mcp-example/tools"
+)
+
+const deviceReportsJSON = `[
+	{"id": 1, "report_name": "devices", "report_agency": "gsa", "date": "2024-01-01", "device": "desktop", "visits": 100},
+	{"id": 2, "report_name": "devices", "report_agency": "gsa", "date": "2024-01-01", "device": "mobile", "visits": 150},
+	{"id": 3, "report_name": "devices", "report_agency": "gsa", "date": "2024-01-02", "device": "desktop", "visits": 120},
+	{"id": 4, "report_name": "devices", "report_agency": "gsa", "date": "2024-01-02", "device": "tablet", "visits": 10}
+]`
+
+// callAggregateReport invokes the aggregate_report tool handler with the given arguments.
+func callAggregateReport(rt *tools.ReportsTool,
+	args models.AggregateArgs) (*mcp.CallToolResultFor[models.AggregateResponse], error) {
+	return rt.AggregateReport(context.Background(), nil, &mcp.CallToolParamsFor[models.AggregateArgs]{
+		Name:      "aggregate_report",
+		Arguments: args,
+	})
+}
+
+func TestReportsTool_AggregateReport(t *testing.T) {
+	t.Parallel()
+
+	rt, requestedURLs := newTestReportsTool(t, deviceReportsJSON, http.StatusOK)
+
+	result, err := callAggregateReport(rt, models.AggregateArgs{
+		ReportName: "devices",
+		GroupBy:    "device",
+		Functions:  []string{"sum", "avg", "count"},
+	})
+	if err != nil {
+		t.Fatalf("AggregateReport() unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("AggregateReport() returned error result: %+v", result.Content)
+	}
+
+	if len(*requestedURLs) != 1 {
+		t.Errorf("Expected 1 request for a short page, got %d", len(*requestedURLs))
+	}
+
+	response := result.StructuredContent
+	if response.Records != 4 || len(response.Rows) != 3 {
+		t.Fatalf("Aggregated %d records into %d groups, want 4 into 3", response.Records, len(response.Rows))
+	}
+
+	top := response.Rows[0]
+	if top.Group != "desktop" || top.Count != 2 || top.Values["sum_visits"] != 220 || top.Values["avg_visits"] != 110 {
+		t.Errorf("Top group = %+v, want desktop with 2 rows, sum 220 and avg 110", top)
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, expected := range []string{
+		"Aggregated Report: devices by device",
+		"3 groups from 4 records",
+		"| device | count | sum_visits | avg_visits |",
+		"| desktop | 2 | 220 | 110 |",
+		"| tablet | 1 | 10 | 10 |",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Result text missing %q:\n%s", expected, text)
+		}
+	}
+}
+
+func TestReportsTool_AggregateReport_FetchesAllPages(t *testing.T) {
+	t.Parallel()
+
+	rt, requestedURLs := newPagedReportsTool(t, 2500)
+
+	result, err := callAggregateReport(rt, models.AggregateArgs{ReportName: "traffic", GroupBy: "date"})
+	if err != nil {
+		t.Fatalf("AggregateReport() unexpected error: %v", err)
+	}
+
+	if len(*requestedURLs) != 3 {
+		t.Errorf("Requests = %d, want 3", len(*requestedURLs))
+	}
+
+	response := result.StructuredContent
+	if response.Records != 2500 || len(response.Rows) != 1 || response.Truncated {
+		t.Fatalf("Response = %d records in %d groups (truncated %v), want 2500 in 1",
+			response.Records, len(response.Rows), response.Truncated)
+	}
+	// Visits of the paged mock are 0..2499
+	if sum := response.Rows[0].Values["sum_visits"]; sum != 2499*2500/2 {
+		t.Errorf("sum_visits = %v, want %d", sum, 2499*2500/2)
+	}
+}
+
+func TestReportsTool_AggregateReport_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   models.AggregateArgs
+		errMsg string
+	}{
+		{
+			name:   "missing group_by",
+			args:   models.AggregateArgs{ReportName: "devices"},
+			errMsg: "group_by is required",
+		},
+		{
+			name:   "unknown group_by",
+			args:   models.AggregateArgs{ReportName: "devices", GroupBy: "visits"},
+			errMsg: "invalid group_by field 'visits'",
+		},
+		{
+			name:   "unknown metric",
+			args:   models.AggregateArgs{ReportName: "devices", GroupBy: "device", Metrics: []string{"device"}},
+			errMsg: "invalid metric 'device'",
+		},
+		{
+			name:   "unknown function",
+			args:   models.AggregateArgs{ReportName: "devices", GroupBy: "device", Functions: []string{"median"}},
+			errMsg: "invalid function 'median'",
+		},
+		{
+			name:   "invalid report type",
+			args:   models.AggregateArgs{ReportName: "unknown", GroupBy: "device"},
+			errMsg: "invalid report type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt, requestedURLs := newTestReportsTool(t, deviceReportsJSON, http.StatusOK)
+
+			_, err := callAggregateReport(rt, tt.args)
+			if err == nil {
+				t.Fatal("AggregateReport() expected error but got none")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("AggregateReport() error = %v, want error containing %q", err, tt.errMsg)
+			}
+			if len(*requestedURLs) != 0 {
+				t.Errorf("Expected no requests, got %d", len(*requestedURLs))
+			}
+		})
+	}
+}
+
+func TestReportsTool_AggregateReport_StructuredError(t *testing.T) {
+	t.Parallel()
+
+	rt, _ := newTestReportsTool(t, `{"error":"unavailable"}`, http.StatusInternalServerError)
+
+	result, err := callAggregateReport(rt, models.AggregateArgs{ReportName: "devices", GroupBy: "device"})
+	if err != nil {
+		t.Fatalf("AggregateReport() unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("AggregateReport() should return an error result")
+	}
+	if result.StructuredContent.Error == nil ||
+		!strings.Contains(result.StructuredContent.Error.Message, "status 500") {
+		t.Errorf("StructuredContent.Error = %+v, want API failure message", result.StructuredContent.Error)
+	}
+}
+
+func TestReportsTool_AggregateReport_OutputSchema(t *testing.T) {
+	t.Parallel()
+
+	rt, _ := newTestReportsTool(t, deviceReportsJSON, http.StatusOK)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+
+	tool := &mcp.Tool{Name: "aggregate_report", Description: tools.AggregateReportToolDescription}
+	mcp.AddTool(server, tool, rt.AggregateReport)
+
+	if tool.OutputSchema == nil {
+		t.Fatal("aggregate_report should declare an output schema")
+	}
+	for _, property := range []string{"group_by", "columns", "rows", "records", "truncated"} {
+		if _, ok := tool.OutputSchema.Properties[property]; !ok {
+			t.Errorf("OutputSchema is missing property %q", property)
+		}
+	}
+}
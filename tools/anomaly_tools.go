@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+// Defaults of the detect_anomalies tool: a one-week rolling window over the last 30 days of visits.
+const (
+	defaultAnomalyWindow    = 7
+	defaultAnomalyDateRange = "last 30 days"
+)
+
+// DetectAnomalies implements the detect_anomalies tool.
+// It builds a daily series of a metric from a date-keyed report and flags the days that fall
+// outside a rolling z-score or IQR band computed from the days before them.
+func (rt *ReportsTool) DetectAnomalies(ctx context.Context, _ *mcp.ServerSession,
+	params *mcp.CallToolParamsFor[models.AnomalyArgs]) (*mcp.CallToolResultFor[models.AnomalyResponse], error) {
+	args := params.Arguments
+
+	rt.logger.InfoContext(ctx, "Processing detect_anomalies tool call",
+		"report_name", args.ReportName,
+		"metric", args.Metric,
+		"method", args.Method,
+		"window", args.Window)
+
+	// Set defaults if not provided
+	if args.Metric == "" {
+		args.Metric = defaultAggregateMetric
+	}
+	detection := models.AnomalyDetection{
+		Method:    models.AnomalyMethod(args.Method),
+		Window:    args.Window,
+		Threshold: args.Threshold,
+	}
+	if detection.Method == "" {
+		detection.Method = models.AnomalyZScore
+	}
+	if detection.Window == 0 {
+		detection.Window = defaultAnomalyWindow
+	}
+	if detection.Threshold == 0 {
+		detection.Threshold = detection.Method.DefaultThreshold()
+	}
+	if args.After == "" && args.Before == "" && args.DateRange == "" {
+		args.DateRange = defaultAnomalyDateRange
+	}
+
+	if !models.IsMetricField(args.Metric) {
+		return nil, fmt.Errorf("invalid parameters: invalid metric '%s'", args.Metric)
+	}
+	if err := detection.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	request, err := rt.prepareRequest(models.ReportArgs{
+		ReportName: args.ReportName,
+		After:      args.After,
+		Before:     args.Before,
+		DateRange:  args.DateRange,
+		AgencyName: args.AgencyName,
+		Domain:     args.Domain,
+		MaxRecords: args.MaxRecords,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	reports, pagination, err := rt.fetch(ctx, request, true, args.MaxRecords)
+	if err != nil {
+		result := anomalyErrorResult("Request failed: " + err.Error())
+		return result, nil //nolint:nilerr // MCP tools return nil error when IsError is true
+	}
+
+	if len(reports) == 0 {
+		return anomalyErrorResult("No data found for report: " + args.ReportName), nil
+	}
+
+	series, err := models.DailySeries(reports, args.Metric)
+	if err != nil {
+		result := anomalyErrorResult("Report is not date-keyed: " + err.Error())
+		return result, nil //nolint:nilerr // MCP tools return nil error when IsError is true
+	}
+
+	response := models.AnomalyResponse{
+		ReportName: args.ReportName,
+		Metric:     args.Metric,
+		Method:     string(detection.Method),
+		Window:     detection.Window,
+		Threshold:  detection.Threshold,
+		Days:       len(series),
+		Anomalies:  detection.Detect(series),
+		Records:    len(reports),
+		Truncated:  pagination.Truncated,
+	}
+
+	text := fmt.Sprintf("Anomaly Detection: %s %s\n\n%s", args.ReportName, args.Metric, formatAnomalies(response, series))
+	if warning := rt.quotaWarning(); warning != "" {
+		text += "\n\n" + warning
+	}
+
+	return &mcp.CallToolResultFor[models.AnomalyResponse]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+		StructuredContent: response,
+	}, nil
+}
+
+// anomalyErrorResult creates an error tool result whose structured content carries the message.
+func anomalyErrorResult(message string) *mcp.CallToolResultFor[models.AnomalyResponse] {
+	return &mcp.CallToolResultFor[models.AnomalyResponse]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: message},
+		},
+		StructuredContent: models.AnomalyResponse{
+			Anomalies: []models.Anomaly{},
+			Error:     &models.Error{Message: message},
+		},
+		IsError: true,
+	}
+}
+
+// formatAnomalies summarizes the analyzed series and renders the flagged days as a Markdown table.
+func formatAnomalies(response models.AnomalyResponse, series []models.SeriesPoint) string {
+	summary := fmt.Sprintf("%d days", response.Days)
+	if len(series) > 0 {
+		summary += fmt.Sprintf(" (%s..%s)", series[0].Date, series[len(series)-1].Date)
+	}
+	summary += fmt.Sprintf(" from %d records, %s band of %s over a %d-day window",
+		response.Records, response.Method, formatNumber(response.Threshold), response.Window)
+	if response.Truncated {
+		summary += " (truncated at max_records; more data is available)"
+	}
+
+	if response.Days <= response.Window {
+		return summary + fmt.Sprintf(".\n\nNot enough history: at least %d days are needed.", response.Window+1)
+	}
+	if len(response.Anomalies) == 0 {
+		return summary + ".\n\nNo anomalies found."
+	}
+
+	rows := make([][]string, len(response.Anomalies))
+	for i, anomaly := range response.Anomalies {
+		rows[i] = []string{
+			anomaly.Date,
+			anomaly.Direction,
+			formatNumber(anomaly.Actual),
+			formatNumber(anomaly.Expected),
+			formatNumber(anomaly.Lower) + ".." + formatNumber(anomaly.Upper),
+			formatSignedNumber(anomaly.Score),
+		}
+	}
+	header := []string{"date", "direction", "actual", "expected", "band", "score"}
+	return fmt.Sprintf("%s; %d anomalies:\n\n%s", summary, len(response.Anomalies), markdownTable(header, rows))
+}
@@ -0,0 +1,169 @@
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/models"
+	"github.com/rameshsunkara/go-mcp-example/tools"
+)
+
+// dailyTrafficJSON returns traffic rows with the given daily visits, starting on 2024-02-14.
+func dailyTrafficJSON(visits ...int) string {
+	rows := make([]string, len(visits))
+	for i, value := range visits {
+		rows[i] = fmt.Sprintf(`{"id": %d, "report_name": "traffic", "date": "2024-02-%02d", "visits": %d}`,
+			i+1, 14+i, value)
+	}
+	return "[" + strings.Join(rows, ",") + "]"
+}
+
+// callDetectAnomalies invokes the detect_anomalies tool handler with the given arguments.
+func callDetectAnomalies(rt *tools.ReportsTool,
+	args models.AnomalyArgs) (*mcp.CallToolResultFor[models.AnomalyResponse], error) {
+	return rt.DetectAnomalies(context.Background(), nil, &mcp.CallToolParamsFor[models.AnomalyArgs]{
+		Name:      "detect_anomalies",
+		Arguments: args,
+	})
+}
+
+func TestReportsTool_DetectAnomalies(t *testing.T) {
+	t.Parallel()
+
+	// The default date range of the last 30 days starts on 2024-02-14
+	rt, requestedURLs := newPeriodReportsTool(t, map[string]string{
+		"2024-02-14": dailyTrafficJSON(1000, 1100, 900, 1000, 1050, 950, 1000, 5000, 1000, 1020),
+	})
+
+	result, err := callDetectAnomalies(rt, models.AnomalyArgs{ReportName: "traffic"})
+	if err != nil {
+		t.Fatalf("DetectAnomalies() unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("DetectAnomalies() returned error result: %+v", result.Content)
+	}
+
+	expectedURL := "https://api.example.com/reports/traffic/data?after=2024-02-14&before=2024-03-14&limit=1000&page=1"
+	if len(*requestedURLs) != 1 || (*requestedURLs)[0] != expectedURL {
+		t.Errorf("Requested URLs = %v, want [%s]", *requestedURLs, expectedURL)
+	}
+
+	response := result.StructuredContent
+	if response.Metric != "visits" || response.Method != "zscore" || response.Window != 7 || response.Threshold != 3 {
+		t.Errorf("Response defaults = %s/%s/%d/%v, want visits/zscore/7/3",
+			response.Metric, response.Method, response.Window, response.Threshold)
+	}
+	if response.Days != 10 || response.Records != 10 {
+		t.Errorf("Days = %d, Records = %d, want 10 and 10", response.Days, response.Records)
+	}
+	if len(response.Anomalies) != 1 || response.Anomalies[0].Date != "2024-02-21" ||
+		response.Anomalies[0].Direction != "spike" || response.Anomalies[0].Expected != 1000 {
+		t.Fatalf("Anomalies = %+v, want a single spike on 2024-02-21", response.Anomalies)
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, expected := range []string{
+		"10 days (2024-02-14..2024-02-23) from 10 records, zscore band of 3 over a 7-day window; 1 anomalies:",
+		"| date | direction | actual | expected | band | score |",
+		"| 2024-02-21 | spike | 5000 | 1000 |",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Result text missing %q:\n%s", expected, text)
+		}
+	}
+}
+
+func TestReportsTool_DetectAnomalies_NotEnoughHistory(t *testing.T) {
+	t.Parallel()
+
+	rt, _ := newPeriodReportsTool(t, map[string]string{
+		"2024-02-14": dailyTrafficJSON(1000, 1100, 900),
+	})
+
+	result, err := callDetectAnomalies(rt, models.AnomalyArgs{ReportName: "traffic", Method: "iqr"})
+	if err != nil {
+		t.Fatalf("DetectAnomalies() unexpected error: %v", err)
+	}
+	if result.StructuredContent.Threshold != 1.5 {
+		t.Errorf("Threshold = %v, want iqr default 1.5", result.StructuredContent.Threshold)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "at least 8 days are needed") {
+		t.Errorf("Result text should explain the missing history:\n%s", text)
+	}
+}
+
+func TestReportsTool_DetectAnomalies_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   models.AnomalyArgs
+		errMsg string
+	}{
+		{
+			name:   "invalid metric",
+			args:   models.AnomalyArgs{ReportName: "traffic", Metric: "date"},
+			errMsg: "invalid metric 'date'",
+		},
+		{
+			name:   "invalid method",
+			args:   models.AnomalyArgs{ReportName: "traffic", Method: "mad"},
+			errMsg: "invalid method 'mad'",
+		},
+		{
+			name:   "window too small",
+			args:   models.AnomalyArgs{ReportName: "traffic", Window: 1},
+			errMsg: "window must be >= 2",
+		},
+		{
+			name:   "invalid date range",
+			args:   models.AnomalyArgs{ReportName: "traffic", DateRange: "lately"},
+			errMsg: "invalid parameters",
+		},
+		{
+			name:   "invalid report type",
+			args:   models.AnomalyArgs{ReportName: "unknown"},
+			errMsg: "invalid report type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt, requestedURLs := newPeriodReportsTool(t, nil)
+
+			_, err := callDetectAnomalies(rt, tt.args)
+			if err == nil {
+				t.Fatal("DetectAnomalies() expected error but got none")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("DetectAnomalies() error = %v, want error containing %q", err, tt.errMsg)
+			}
+			if len(*requestedURLs) != 0 {
+				t.Errorf("Expected no requests, got %d", len(*requestedURLs))
+			}
+		})
+	}
+}
+
+func TestReportsTool_DetectAnomalies_StructuredError(t *testing.T) {
+	t.Parallel()
+
+	rt, _ := newPeriodReportsTool(t, nil)
+
+	result, err := callDetectAnomalies(rt, models.AnomalyArgs{ReportName: "traffic"})
+	if err != nil {
+		t.Fatalf("DetectAnomalies() unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("DetectAnomalies() should return an error result when no data is found")
+	}
+	if result.StructuredContent.Error == nil ||
+		!strings.Contains(result.StructuredContent.Error.Message, "No data found") {
+		t.Errorf("StructuredContent.Error = %+v, want no data message", result.StructuredContent.Error)
+	}
+}
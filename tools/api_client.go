@@ -1,8 +1,21 @@
 package tools
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/metrics"
+	"github.com/rameshsunkara/go-mcp-example/models"
+	"github.com/rameshsunkara/go-mcp-example/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // APIClient configuration and utilities for making API requests.
@@ -10,6 +23,11 @@ type APIClient struct {
 	BaseURL    string
 	APIKey     string
 	HTTPClient HTTPClientInterface
+	Cache      Cache            // Optional response cache, nil disables caching
+	Retry      RetryPolicy      // Retry behavior, the zero value disables retries
+	Limiter    *RateLimiter     // Optional client-side rate limiter, nil disables limiting
+	Metrics    *metrics.Metrics // Optional metrics, nil disables recording
+	Tracer     *tracing.Tracer  // Optional tracer, nil disables tracing
 }
 
 // HTTPClientInterface defines the interface for HTTP clients (for testing).
@@ -36,14 +54,171 @@ func NewAPIClientWithHTTPClient(baseURL, apiKey string, httpClient HTTPClientInt
 }
 
 // DoRequest makes an HTTP request with the configured headers.
+// Idempotent requests are retried on transport errors and retryable status codes
+// according to the retry policy, honoring Retry-After and the request context deadline.
+// When tracing, the request is covered by a client span whose trace context is sent upstream.
 func (c *APIClient) DoRequest(req *http.Request) (*http.Response, error) {
+	if c.Tracer == nil {
+		return c.doRequest(req)
+	}
+
+	report := reportName(req.URL)
+	ctx, span := c.Tracer.Start(req.Context(), req.Method+" "+report, trace.SpanKindClient,
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Host),
+		attribute.String("url.path", req.URL.Path),
+		attribute.String("dap.report", report))
+	defer span.End()
+
+	req = req.WithContext(ctx)
+	tracing.Inject(ctx, req.Header)
+	resp, err := c.doRequest(req)
+	switch {
+	case err != nil:
+		span.SetStatus(codes.Error, err.Error())
+	case resp.StatusCode >= http.StatusBadRequest:
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	default:
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+	return resp, err
+}
+
+// doRequest makes the request, retrying it according to the retry policy.
+func (c *APIClient) doRequest(req *http.Request) (*http.Response, error) {
 	// Add standard headers
 	headers := c.HTTPHeaders()
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
 
-	return c.HTTPClient.Do(req)
+	if !c.Retry.enabled() || !isIdempotent(req.Method) || (req.Body != nil && req.Body != http.NoBody) {
+		return c.send(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(req)
+		if errors.Is(err, ErrQuotaExhausted) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		if attempt >= c.Retry.MaxAttempts || ctx.Err() != nil {
+			return resp, wrapAttemptsError(err, attempt)
+		}
+
+		var delay time.Duration
+		switch {
+		case err != nil:
+			delay = c.Retry.backoff(attempt)
+		case isRetryableStatus(resp.StatusCode):
+			delay = c.Retry.backoff(attempt)
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if c.Retry.MaxDelay > 0 && retryAfter > c.Retry.MaxDelay {
+					// The server asked for a longer pause than we are willing to wait
+					return resp, nil
+				}
+				delay = retryAfter
+			}
+		default:
+			return resp, nil
+		}
+
+		// Give up early if the delay would outlive the request deadline
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return resp, wrapAttemptsError(err, attempt)
+		}
+
+		if resp != nil {
+			// Drain the body so the underlying connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("request cancelled while waiting to retry after %d attempts: %w",
+				attempt, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// send makes a single HTTP request, waiting for the rate limiter and recording the
+// quota reported in the response.
+func (c *APIClient) send(req *http.Request) (*http.Response, error) {
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	c.Metrics.ObserveAPIRequest(reportName(req.URL), status, time.Since(start))
+
+	if c.Limiter != nil {
+		c.Limiter.Observe(resp)
+	}
+	return resp, err
+}
+
+// reportName returns the report requested by a report data URL such as .../reports/realtime/data,
+// or "other" for any other URL.
+func reportName(u *url.URL) string {
+	segments := strings.Split(u.Path, "/")
+	for i := len(segments) - 3; i >= 0; i-- {
+		if segments[i] == "reports" && segments[i+2] == "data" {
+			if models.ReportType(segments[i+1]).IsValid() {
+				return segments[i+1]
+			}
+			break
+		}
+	}
+	return "other"
+}
+
+// Ping checks that the API is reachable and accepts the API key by fetching a single row of the
+// realtime report, bypassing the response cache.
+func (c *APIClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/reports/realtime/data?limit=1", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create ping request: %w", err)
+	}
+
+	resp, err := c.DoRequest(req)
+	if err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ping failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Quota returns the remaining API request quota, if known.
+func (c *APIClient) Quota() Quota {
+	if c.Limiter == nil {
+		return Quota{}
+	}
+	return c.Limiter.Quota()
+}
+
+// wrapAttemptsError annotates a request error with the number of attempts made.
+func wrapAttemptsError(err error, attempts int) error {
+	if err == nil || attempts == 1 {
+		return err
+	}
+	return fmt.Errorf("request failed after %d attempts: %w", attempts, err)
 }
 
 // HTTPHeaders returns the HTTP headers needed for API requests.
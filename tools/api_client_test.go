@@ -2,12 +2,21 @@ package tools_test
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/rameshsunkara/go-mcp-example/metrics"
 	"github.com/rameshsunkara/go-mcp-example/tools"
+	"github.com/rameshsunkara/go-mcp-example/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // MockHTTPClient is a mock implementation of HTTPClientInterface for testing.
@@ -397,6 +406,96 @@ func TestAPIClient_DoRequest_HeaderOverride(t *testing.T) {
 	}
 }
 
+func TestAPIClient_Ping(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		statusCode int
+		err        error
+		errMsg     string
+	}{
+		{name: "reachable", statusCode: http.StatusOK},
+		{name: "rejected API key", statusCode: http.StatusForbidden, errMsg: "ping failed with status 403"},
+		{name: "unreachable", err: &mockError{message: "connection refused"}, errMsg: "connection refused"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requestedURL string
+			mockClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					requestedURL = req.URL.String()
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &http.Response{
+						StatusCode: tt.statusCode,
+						Body:       io.NopCloser(strings.NewReader(`[]`)),
+					}, nil
+				},
+			}
+			client := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+
+			err := client.Ping(context.Background())
+			if tt.errMsg == "" && err != nil {
+				t.Errorf("Ping() unexpected error: %v", err)
+			}
+			if tt.errMsg != "" && (err == nil || !strings.Contains(err.Error(), tt.errMsg)) {
+				t.Errorf("Ping() error = %v, want error containing %q", err, tt.errMsg)
+			}
+			if want := "https://api.example.com/reports/realtime/data?limit=1"; requestedURL != want {
+				t.Errorf("Ping() requested %s, want %s", requestedURL, want)
+			}
+		})
+	}
+}
+
+func TestAPIClient_DoRequest_RecordsMetrics(t *testing.T) {
+	t.Parallel()
+
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if strings.Contains(req.URL.Path, "/domains/") {
+				return nil, &mockError{message: "connection refused"}
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`[]`))}, nil
+		},
+	}
+	client := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+	client.Metrics = metrics.New()
+
+	for _, path := range []string{
+		"/reports/realtime/data",
+		"/agencies/nasa/reports/browsers/data?limit=10",
+		"/reports/domains/data",
+		"/reports/not-a-report/data",
+	} {
+		req, err := http.NewRequest(http.MethodGet, "https://api.example.com"+path, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		if resp, doErr := client.DoRequest(req); doErr == nil {
+			resp.Body.Close()
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	client.Metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, want := range []string{
+		`dap_api_request_duration_seconds_count{report="realtime",status="200"} 1`,
+		`dap_api_request_duration_seconds_count{report="browsers",status="200"} 1`,
+		`dap_api_request_duration_seconds_count{report="domains",status="error"} 1`,
+		`dap_api_request_duration_seconds_count{report="other",status="200"} 1`,
+	} {
+		if !strings.Contains(rec.Body.String(), want+"\n") {
+			t.Errorf("Metrics are missing %s:\n%s", want, rec.Body.String())
+		}
+	}
+}
+
 // mockError is a helper for testing error scenarios.
 type mockError struct {
 	message string
@@ -406,6 +505,63 @@ func (e *mockError) Error() string {
 	return e.message
 }
 
+// keptSpans records the exported spans and keeps them after the tracer shuts down.
+type keptSpans struct {
+	*tracetest.InMemoryExporter
+}
+
+func (keptSpans) Shutdown(context.Context) error { return nil }
+
+func TestAPIClient_DoRequest_Traces(t *testing.T) {
+	t.Parallel()
+
+	var traceParent string
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			traceParent = req.Header.Get("traceparent")
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		},
+	}
+	exporter := keptSpans{InMemoryExporter: tracetest.NewInMemoryExporter()}
+	tracer := tracing.NewTracer(exporter, tracing.Resource{ServiceName: "test"})
+	client := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+	client.Tracer = tracer
+
+	ctx, parent := tracer.Start(context.Background(), "tools/call get_report", trace.SpanKindServer)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.example.com/reports/realtime/data", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	resp, err := client.DoRequest(req)
+	if err != nil {
+		t.Fatalf("DoRequest() unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	parent.End()
+	if err = tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() unexpected error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[0].Name != "GET realtime" {
+		t.Fatalf("Exported spans = %v, want the GET realtime client span and its parent", spans)
+	}
+	span := spans[0]
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() || span.SpanKind != trace.SpanKindClient {
+		t.Errorf("Client span %+v is not a child of the parent span", span.SpanContext)
+	}
+	want := "00-" + parent.SpanContext().TraceID().String() + "-" + span.SpanContext.SpanID().String() + "-01"
+	if traceParent != want {
+		t.Errorf("Request traceparent = %q, want %q", traceParent, want)
+	}
+	if span.Status.Code != codes.Error || span.Status.Description != "Not Found" {
+		t.Errorf("Client span status = %+v, want error Not Found", span.Status)
+	}
+	if !slices.Contains(span.Attributes, attribute.Int("http.response.status_code", http.StatusNotFound)) {
+		t.Errorf("Client span attributes = %v, want http.response.status_code=404", span.Attributes)
+	}
+}
+
 func TestAPIClient_Integration(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,242 @@
+package tools
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/config"
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+// Cache stores raw API response bodies keyed by canonical request URL.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// NewCache creates the response cache selected by the configuration.
+// It returns nil when caching is disabled.
+func NewCache(cfg *config.Config) (Cache, error) {
+	switch strings.ToLower(cfg.CacheBackend) {
+	case "", "none":
+		return nil, nil //nolint:nilnil // a nil cache disables caching
+	case "memory":
+		return NewMemoryCache(cfg.CacheSize), nil
+	case "file":
+		return NewFileCache(cfg.CacheDir)
+	default:
+		return nil, fmt.Errorf("unknown cache backend '%s'", cfg.CacheBackend)
+	}
+}
+
+// CachePolicy decides how long report responses stay cached.
+type CachePolicy struct {
+	DefaultTTL    time.Duration
+	RealtimeTTL   time.Duration
+	HistoricalTTL time.Duration
+}
+
+// NewCachePolicy creates a CachePolicy from the configured TTLs.
+func NewCachePolicy(cfg *config.Config) CachePolicy {
+	return CachePolicy{
+		DefaultTTL:    cfg.CacheTTL,
+		RealtimeTTL:   cfg.CacheRealtimeTTL,
+		HistoricalTTL: cfg.CacheHistoricalTTL,
+	}
+}
+
+// TTL returns the cache lifetime for a report request. Realtime data changes minute by
+// minute, while date ranges that ended before today are not expected to change anymore.
+func (p CachePolicy) TTL(request models.ReportRequest, now time.Time) time.Duration {
+	if models.ReportType(request.ReportName) == models.ReportTypeActiveUsers {
+		return p.RealtimeTTL
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if before, err := time.Parse(time.DateOnly, request.Parameters.Before); err == nil && before.Before(today) {
+		return p.HistoricalTTL
+	}
+
+	return p.DefaultTTL
+}
+
+// CacheKey returns the canonical form of a request URL, with a lower-cased host
+// and sorted query parameters, so equivalent requests share a cache entry.
+func CacheKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.RawQuery = u.Query().Encode()
+	u.Fragment = ""
+	return u.String()
+}
+
+// memoryCacheEntry is a single value held by MemoryCache.
+type memoryCacheEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryCache is an in-memory LRU cache whose entries expire after their TTL.
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+// NewMemoryCache creates a MemoryCache holding at most capacity entries.
+// A non-positive capacity leaves the cache unbounded.
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get returns the cached value for key if present and not expired.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry, ok := elem.Value.(*memoryCacheEntry)
+	if !ok || !c.now().Before(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Set stores value under key for ttl, evicting the least recently used entry when full.
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &memoryCacheEntry{key: key, value: value, expiresAt: c.now().Add(ttl)}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	if c.capacity > 0 && c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		if oldestEntry, ok := oldest.Value.(*memoryCacheEntry); ok {
+			delete(c.entries, oldestEntry.key)
+		}
+	}
+}
+
+// Len returns the number of entries currently held, including expired ones not yet evicted.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// fileCacheEntry is the on-disk representation of a FileCache value.
+type fileCacheEntry struct {
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Value     []byte    `json:"value"`
+}
+
+// FileCache stores cache entries as files in a directory so they survive restarts.
+type FileCache struct {
+	dir string
+	now func() time.Time
+}
+
+// NewFileCache creates a FileCache in dir, creating the directory if needed.
+func NewFileCache(dir string) (*FileCache, error) {
+	if dir == "" {
+		return nil, errors.New("cache directory must not be empty")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &FileCache{dir: dir, now: time.Now}, nil
+}
+
+// Get returns the cached value for key if present and not expired.
+func (c *FileCache) Get(key string) ([]byte, bool) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry fileCacheEntry
+	if unmarshalErr := json.Unmarshal(data, &entry); unmarshalErr != nil || entry.Key != key {
+		return nil, false
+	}
+	if !c.now().Before(entry.ExpiresAt) {
+		_ = os.Remove(path)
+		return nil, false
+	}
+
+	return entry.Value, true
+}
+
+// Set stores value under key for ttl. Write failures are ignored since the cache is best effort.
+func (c *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(fileCacheEntry{Key: key, ExpiresAt: c.now().Add(ttl), Value: value})
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first so readers never observe a partial entry
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if renameErr := os.Rename(tmp.Name(), c.path(key)); renameErr != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// path returns the file used to store key.
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
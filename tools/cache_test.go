@@ -0,0 +1,212 @@
+package tools_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/config"
+	"github.com/rameshsunkara/go-mcp-example/models"
+	"github.com/rameshsunkara/go-mcp-example/tools"
+)
+
+func TestNewCache(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		cfg       config.Config
+		expectNil bool
+		expectErr bool
+	}{
+		{name: "empty backend disables cache", cfg: config.Config{}, expectNil: true},
+		{name: "none backend disables cache", cfg: config.Config{CacheBackend: "none"}, expectNil: true},
+		{name: "memory backend", cfg: config.Config{CacheBackend: "memory", CacheSize: 10}},
+		{name: "file backend", cfg: config.Config{CacheBackend: "file", CacheDir: t.TempDir()}},
+		{name: "file backend without directory", cfg: config.Config{CacheBackend: "file"}, expectErr: true},
+		{name: "unknown backend", cfg: config.Config{CacheBackend: "redis"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cache, err := tools.NewCache(&tt.cfg)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("NewCache() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewCache() unexpected error: %v", err)
+			}
+			if (cache == nil) != tt.expectNil {
+				t.Errorf("NewCache() = %v, expect nil: %v", cache, tt.expectNil)
+			}
+		})
+	}
+}
+
+func TestMemoryCache_GetSet(t *testing.T) {
+	t.Parallel()
+
+	cache := tools.NewMemoryCache(10)
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("Get() on empty cache should miss")
+	}
+
+	cache.Set("key", []byte("value"), time.Minute)
+	value, ok := cache.Get("key")
+	if !ok {
+		t.Fatal("Get() should hit after Set()")
+	}
+	if string(value) != "value" {
+		t.Errorf("Get() = %s, want value", value)
+	}
+
+	cache.Set("zero-ttl", []byte("value"), 0)
+	if _, hit := cache.Get("zero-ttl"); hit {
+		t.Error("Entries with zero TTL should not be cached")
+	}
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	t.Parallel()
+
+	cache := tools.NewMemoryCache(10)
+	cache.Set("key", []byte("value"), 10*time.Millisecond)
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok := cache.Get("key"); ok {
+		t.Error("Get() should miss after TTL expired")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Len() = %d, expired entry should be evicted on read", cache.Len())
+	}
+}
+
+func TestMemoryCache_LRUEviction(t *testing.T) {
+	t.Parallel()
+
+	cache := tools.NewMemoryCache(2)
+	cache.Set("a", []byte("1"), time.Minute)
+	cache.Set("b", []byte("2"), time.Minute)
+
+	// Touch "a" so that "b" becomes the least recently used entry
+	cache.Get("a")
+	cache.Set("c", []byte("3"), time.Minute)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Least recently used entry should have been evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Recently used entry should still be cached")
+	}
+	if _, ok := cache.Get("c"); !ok {
+		t.Error("Newest entry should be cached")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", cache.Len())
+	}
+}
+
+func TestFileCache_GetSet(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cache, err := tools.NewFileCache(dir)
+	if err != nil {
+		t.Fatalf("NewFileCache() unexpected error: %v", err)
+	}
+
+	cache.Set("key", []byte(`[{"id":1}]`), time.Minute)
+
+	// A new cache over the same directory sees entries written by the first one
+	reopened, err := tools.NewFileCache(dir)
+	if err != nil {
+		t.Fatalf("NewFileCache() unexpected error: %v", err)
+	}
+	value, ok := reopened.Get("key")
+	if !ok {
+		t.Fatal("Get() should hit entries persisted on disk")
+	}
+	if string(value) != `[{"id":1}]` {
+		t.Errorf("Get() = %s, want [{\"id\":1}]", value)
+	}
+
+	cache.Set("short", []byte("value"), 10*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	if _, hit := cache.Get("short"); hit {
+		t.Error("Get() should miss after TTL expired")
+	}
+}
+
+func TestCachePolicy_TTL(t *testing.T) {
+	t.Parallel()
+
+	policy := tools.CachePolicy{
+		DefaultTTL:    15 * time.Minute,
+		RealtimeTTL:   time.Minute,
+		HistoricalTTL: 24 * time.Hour,
+	}
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		request  models.ReportRequest
+		expected time.Duration
+	}{
+		{
+			name:     "realtime report",
+			request:  models.ReportRequest{ReportName: "realtime"},
+			expected: time.Minute,
+		},
+		{
+			name:     "open-ended range",
+			request:  models.ReportRequest{ReportName: "traffic"},
+			expected: 15 * time.Minute,
+		},
+		{
+			name: "range ending today",
+			request: models.ReportRequest{
+				ReportName: "traffic",
+				Parameters: models.ReportParams{After: "2024-03-01", Before: "2024-03-15"},
+			},
+			expected: 15 * time.Minute,
+		},
+		{
+			name: "historical range",
+			request: models.ReportRequest{
+				ReportName: "traffic",
+				Parameters: models.ReportParams{After: "2024-02-01", Before: "2024-02-29"},
+			},
+			expected: 24 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := policy.TTL(tt.request, now); got != tt.expected {
+				t.Errorf("TTL() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	t.Parallel()
+
+	a := tools.CacheKey("https://API.example.com/reports/devices/data?page=1&limit=10")
+	b := tools.CacheKey("https://api.example.com/reports/devices/data?limit=10&page=1")
+
+	if a != b {
+		t.Errorf("CacheKey() should be canonical, got %s and %s", a, b)
+	}
+	if a != "https://api.example.com/reports/devices/data?limit=10&page=1" {
+		t.Errorf("CacheKey() = %s", a)
+	}
+}
@@ -0,0 +1,238 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+// ComparePeriods implements the compare_periods tool.
+// It fetches the same report for two periods and returns the per-dimension changes of each metric.
+func (rt *ReportsTool) ComparePeriods(ctx context.Context, _ *mcp.ServerSession,
+	params *mcp.CallToolParamsFor[models.CompareArgs]) (*mcp.CallToolResultFor[models.ComparisonResponse], error) {
+	args := params.Arguments
+
+	rt.logger.InfoContext(ctx, "Processing compare_periods tool call",
+		"report_name", args.ReportName,
+		"period", args.Period,
+		"previous_period", args.PreviousPeriod,
+		"dimension", args.Dimension)
+
+	metrics := args.Metrics
+	if len(metrics) == 0 {
+		metrics = []string{defaultAggregateMetric}
+	}
+	dimension := args.Dimension
+	if dimension == "" {
+		dimension = models.ReportType(args.ReportName).DefaultDimension()
+	}
+
+	current, previous, err := rt.resolvePeriods(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+	if err = validateComparison(dimension, metrics, args.Top); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	currentRequest, err := rt.periodRequest(args, current)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+	previousRequest, err := rt.periodRequest(args, previous)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	currentRows, currentPagination, err := rt.fetch(ctx, currentRequest, true, args.MaxRecords)
+	if err != nil {
+		return comparisonErrorResult(current, err), nil //nolint:nilerr // MCP tools return nil error when IsError is true
+	}
+	previousRows, previousPagination, err := rt.fetch(ctx, previousRequest, true, args.MaxRecords)
+	if err != nil {
+		return comparisonErrorResult(previous, err), nil //nolint:nilerr // MCP tools return nil error when IsError is true
+	}
+
+	rows := models.ComparePeriods(previousRows, currentRows, dimension, metrics)
+	response := models.ComparisonResponse{
+		ReportName: args.ReportName,
+		Dimension:  dimension,
+		CurrentPeriod: models.Period{
+			After:     current.After(),
+			Before:    current.Before(),
+			Truncated: currentPagination.Truncated,
+		},
+		PreviousPeriod: models.Period{
+			After:     previous.After(),
+			Before:    previous.Before(),
+			Truncated: previousPagination.Truncated,
+		},
+		Metrics: metrics,
+		Totals:  models.TotalComparison(rows, metrics),
+		Rows:    rows,
+	}
+	if args.Top > 0 && len(response.Rows) > args.Top {
+		response.Rows = response.Rows[:args.Top]
+	}
+
+	text := fmt.Sprintf("Period Comparison: %s\n\n%s..%s compared to %s..%s (%d records vs %d records):\n\n%s",
+		args.ReportName,
+		response.CurrentPeriod.After, response.CurrentPeriod.Before,
+		response.PreviousPeriod.After, response.PreviousPeriod.Before,
+		len(currentRows), len(previousRows),
+		formatComparisonTable(response))
+	if warning := truncationWarning(response); warning != "" {
+		text += "\n\n" + warning
+	}
+	if warning := rt.quotaWarning(); warning != "" {
+		text += "\n\n" + warning
+	}
+
+	return &mcp.CallToolResultFor[models.ComparisonResponse]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+		StructuredContent: response,
+	}, nil
+}
+
+// resolvePeriods resolves the current period and the period it is compared against, which
+// defaults to the period of equal length right before the current one.
+func (rt *ReportsTool) resolvePeriods(args models.CompareArgs) (models.DateRange, models.DateRange, error) {
+	if args.Period == "" {
+		return models.DateRange{}, models.DateRange{}, errors.New("period is required")
+	}
+
+	now := rt.now()
+	current, err := models.ParseDateExpression(args.Period, now)
+	if err != nil {
+		return models.DateRange{}, models.DateRange{}, fmt.Errorf("invalid period: %w", err)
+	}
+
+	if args.PreviousPeriod == "" {
+		return current, current.Previous(), nil
+	}
+	previous, err := models.ParseDateExpression(args.PreviousPeriod, now)
+	if err != nil {
+		return models.DateRange{}, models.DateRange{}, fmt.Errorf("invalid previous_period: %w", err)
+	}
+	return current, previous, nil
+}
+
+// validateComparison checks the dimension, metrics and top-N arguments of a comparison.
+func validateComparison(dimension string, metrics []string, top int) error {
+	if dimension != "" && !models.IsDimensionField(dimension) {
+		return fmt.Errorf("invalid dimension '%s'. Valid fields: %s",
+			dimension, strings.Join(models.DimensionFieldNames(), ", "))
+	}
+	for _, metric := range metrics {
+		if !models.IsMetricField(metric) {
+			return fmt.Errorf("invalid metric '%s'. Valid metrics: %s",
+				metric, strings.Join(models.MetricFieldNames(), ", "))
+		}
+	}
+	if top < 0 {
+		return fmt.Errorf("top must be >= 1, got %d", top)
+	}
+	return nil
+}
+
+// periodRequest builds the API request for the compared report within the period.
+func (rt *ReportsTool) periodRequest(args models.CompareArgs, period models.DateRange) (models.ReportRequest, error) {
+	return rt.prepareRequest(models.ReportArgs{
+		ReportName: args.ReportName,
+		After:      period.After(),
+		Before:     period.Before(),
+		AgencyName: args.AgencyName,
+		Domain:     args.Domain,
+		MaxRecords: args.MaxRecords,
+	})
+}
+
+// comparisonErrorResult creates an error tool result for a failed fetch of the period.
+func comparisonErrorResult(period models.DateRange,
+	err error) *mcp.CallToolResultFor[models.ComparisonResponse] {
+	message := fmt.Sprintf("Request failed for %s..%s: %s", period.After(), period.Before(), err)
+	return &mcp.CallToolResultFor[models.ComparisonResponse]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: message},
+		},
+		StructuredContent: models.ComparisonResponse{
+			Metrics: []string{},
+			Rows:    []models.ComparisonRow{},
+			Error:   &models.Error{Message: message},
+		},
+		IsError: true,
+	}
+}
+
+// truncationWarning returns a note naming the periods whose data was cut off at max_records, as
+// their totals and changes are incomplete.
+func truncationWarning(response models.ComparisonResponse) string {
+	var periods []string
+	if response.CurrentPeriod.Truncated {
+		periods = append(periods, "current")
+	}
+	if response.PreviousPeriod.Truncated {
+		periods = append(periods, "previous")
+	}
+	if len(periods) == 0 {
+		return ""
+	}
+	return "WARNING: The " + strings.Join(periods, " and ") + " period data was truncated at max_records; " +
+		"more data is available, so the totals and changes are incomplete. Raise max_records for a full comparison."
+}
+
+// formatComparisonTable renders the totals and per-dimension changes as a Markdown table.
+func formatComparisonTable(response models.ComparisonResponse) string {
+	keyColumn := response.Dimension
+	if keyColumn == "" {
+		keyColumn = "rows"
+	}
+
+	header := []string{keyColumn}
+	for _, metric := range response.Metrics {
+		header = append(header, "previous_"+metric, "current_"+metric, "change_"+metric, "pct_change_"+metric)
+	}
+
+	// Without a dimension the only row is the total
+	rows := [][]string{comparisonCells(response.Totals, response.Metrics)}
+	if response.Dimension != "" {
+		for _, row := range response.Rows {
+			rows = append(rows, comparisonCells(row, response.Metrics))
+		}
+	}
+	return markdownTable(header, rows)
+}
+
+// comparisonCells returns the table cells of a comparison row.
+func comparisonCells(row models.ComparisonRow, metrics []string) []string {
+	key := row.Key
+	if key == "" {
+		key = "(none)"
+	}
+
+	cells := []string{key}
+	for _, metric := range metrics {
+		delta := row.Metrics[metric]
+		percent := "n/a"
+		if delta.PercentChange != nil {
+			percent = formatSignedNumber(*delta.PercentChange) + "%"
+		}
+		cells = append(cells, formatNumber(delta.Previous), formatNumber(delta.Current),
+			formatSignedNumber(delta.Change), percent)
+	}
+	return cells
+}
+
+// formatSignedNumber formats a change with an explicit sign and at most two decimal places.
+func formatSignedNumber(value float64) string {
+	if value > 0 {
+		return "+" + formatNumber(value)
+	}
+	return formatNumber(value)
+}
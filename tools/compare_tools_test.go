@@ -0,0 +1,253 @@
+package tools_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/config"
+	"github.com/rameshsunkara/go-mcp-example/models"
+	"github.com/rameshsunkara/go-mcp-example/tools"
+)
+
+// newPeriodReportsTool creates a ReportsTool whose mock API serves the body registered for the
+// requested after date, with its clock fixed at 2024-03-15.
+func newPeriodReportsTool(t *testing.T, bodies map[string]string) (*tools.ReportsTool, *[]string) {
+	t.Helper()
+
+	var requestedURLs []string
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			requestedURLs = append(requestedURLs, req.URL.String())
+			body, ok := bodies[req.URL.Query().Get("after")]
+			if !ok {
+				body = "[]"
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	apiClient := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+	clock := func() time.Time { return time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC) }
+	return tools.NewReportsToolWithClock(logger, &config.Config{}, apiClient, clock), &requestedURLs
+}
+
+// callComparePeriods invokes the compare_periods tool handler with the given arguments.
+func callComparePeriods(rt *tools.ReportsTool,
+	args models.CompareArgs) (*mcp.CallToolResultFor[models.ComparisonResponse], error) {
+	return rt.ComparePeriods(context.Background(), nil, &mcp.CallToolParamsFor[models.CompareArgs]{
+		Name:      "compare_periods",
+		Arguments: args,
+	})
+}
+
+func TestReportsTool_ComparePeriods(t *testing.T) {
+	t.Parallel()
+
+	rt, requestedURLs := newPeriodReportsTool(t, map[string]string{
+		"2024-02-01": `[
+			{"id": 1, "date": "2024-02-01", "device": "desktop", "visits": 100},
+			{"id": 2, "date": "2024-02-01", "device": "mobile", "visits": 200},
+			{"id": 3, "date": "2024-02-02", "device": "mobile", "visits": 100}
+		]`,
+		"2024-01-01": `[
+			{"id": 4, "date": "2024-01-01", "device": "desktop", "visits": 200},
+			{"id": 5, "date": "2024-01-01", "device": "mobile", "visits": 150},
+			{"id": 6, "date": "2024-01-01", "device": "tablet", "visits": 50}
+		]`,
+	})
+
+	result, err := callComparePeriods(rt, models.CompareArgs{ReportName: "devices", Period: "last month"})
+	if err != nil {
+		t.Fatalf("ComparePeriods() unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("ComparePeriods() returned error result: %+v", result.Content)
+	}
+
+	expectedURLs := []string{
+		"https://api.example.com/reports/devices/data?after=2024-02-01&before=2024-02-29&limit=1000&page=1",
+		"https://api.example.com/reports/devices/data?after=2024-01-01&before=2024-01-31&limit=1000&page=1",
+	}
+	if strings.Join(*requestedURLs, "\n") != strings.Join(expectedURLs, "\n") {
+		t.Errorf("Requested URLs = %v, want %v", *requestedURLs, expectedURLs)
+	}
+
+	response := result.StructuredContent
+	if response.Dimension != "device" {
+		t.Errorf("Dimension = %q, want default dimension device", response.Dimension)
+	}
+	if response.PreviousPeriod != (models.Period{After: "2024-01-01", Before: "2024-01-31"}) {
+		t.Errorf("PreviousPeriod = %+v, want January 2024", response.PreviousPeriod)
+	}
+
+	totals := response.Totals.Metrics["visits"]
+	if totals.Previous != 400 || totals.Current != 400 || totals.Change != 0 {
+		t.Errorf("Totals = %+v, want 400 -> 400", totals)
+	}
+
+	// Rows are ordered by absolute change: mobile +150, desktop -100, tablet -50
+	expectedKeys := []string{"mobile", "desktop", "tablet"}
+	for i, key := range expectedKeys {
+		if response.Rows[i].Key != key {
+			t.Errorf("Row %d = %q, want %q", i, response.Rows[i].Key, key)
+		}
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, expected := range []string{
+		"2024-02-01..2024-02-29 compared to 2024-01-01..2024-01-31 (3 records vs 3 records)",
+		"| device | previous_visits | current_visits | change_visits | pct_change_visits |",
+		"| total | 400 | 400 | 0 | 0% |",
+		"| mobile | 150 | 300 | +150 | +100% |",
+		"| tablet | 50 | 0 | -50 | -100% |",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Result text missing %q:\n%s", expected, text)
+		}
+	}
+}
+
+func TestReportsTool_ComparePeriods_ExplicitPreviousPeriod(t *testing.T) {
+	t.Parallel()
+
+	rt, requestedURLs := newPeriodReportsTool(t, map[string]string{
+		"2024-03-01": `[{"id": 1, "date": "2024-03-01", "visits": 120}]`,
+		"2023-03-01": `[{"id": 2, "date": "2023-03-01", "visits": 0}]`,
+	})
+
+	result, err := callComparePeriods(rt, models.CompareArgs{
+		ReportName:     "traffic",
+		Period:         "2024-03",
+		PreviousPeriod: "2023-03",
+	})
+	if err != nil {
+		t.Fatalf("ComparePeriods() unexpected error: %v", err)
+	}
+
+	if len(*requestedURLs) != 2 || !strings.Contains((*requestedURLs)[1], "after=2023-03-01&before=2023-03-31") {
+		t.Errorf("Requested URLs = %v, want March 2023 as previous period", *requestedURLs)
+	}
+
+	response := result.StructuredContent
+	if response.Dimension != "" || len(response.Rows) != 1 {
+		t.Fatalf("Response = %+v, want a single total row for traffic", response)
+	}
+	if delta := response.Totals.Metrics["visits"]; delta.Change != 120 || delta.PercentChange != nil {
+		t.Errorf("Totals = %+v, want +120 without percentage", delta)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "| total | 0 | 120 | +120 | n/a |") {
+		t.Errorf("Result text missing total row:\n%s", text)
+	}
+}
+
+func TestReportsTool_ComparePeriods_Truncated(t *testing.T) {
+	t.Parallel()
+
+	rt, _ := newPeriodReportsTool(t, map[string]string{
+		"2024-02-01": `[
+			{"id": 1, "date": "2024-02-01", "device": "desktop", "visits": 100},
+			{"id": 2, "date": "2024-02-01", "device": "mobile", "visits": 200},
+			{"id": 3, "date": "2024-02-02", "device": "mobile", "visits": 100}
+		]`,
+		"2024-01-01": `[{"id": 4, "date": "2024-01-01", "device": "desktop", "visits": 200}]`,
+	})
+
+	result, err := callComparePeriods(rt, models.CompareArgs{ReportName: "devices", Period: "last month", MaxRecords: 2})
+	if err != nil {
+		t.Fatalf("ComparePeriods() unexpected error: %v", err)
+	}
+
+	response := result.StructuredContent
+	if !response.CurrentPeriod.Truncated || response.PreviousPeriod.Truncated {
+		t.Errorf("Truncated = %v (current), %v (previous), want only the current period truncated",
+			response.CurrentPeriod.Truncated, response.PreviousPeriod.Truncated)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "WARNING: The current period data was truncated at max_records") {
+		t.Errorf("Result text missing truncation warning:\n%s", text)
+	}
+}
+
+func TestReportsTool_ComparePeriods_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   models.CompareArgs
+		errMsg string
+	}{
+		{
+			name:   "missing period",
+			args:   models.CompareArgs{ReportName: "devices"},
+			errMsg: "period is required",
+		},
+		{
+			name:   "invalid period",
+			args:   models.CompareArgs{ReportName: "devices", Period: "soon"},
+			errMsg: "invalid period",
+		},
+		{
+			name:   "invalid dimension",
+			args:   models.CompareArgs{ReportName: "devices", Period: "last month", Dimension: "visits"},
+			errMsg: "invalid dimension 'visits'",
+		},
+		{
+			name:   "invalid metric",
+			args:   models.CompareArgs{ReportName: "devices", Period: "last month", Metrics: []string{"hits"}},
+			errMsg: "invalid metric 'hits'",
+		},
+		{
+			name:   "invalid report type",
+			args:   models.CompareArgs{ReportName: "unknown", Period: "last month"},
+			errMsg: "invalid report type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt, requestedURLs := newPeriodReportsTool(t, nil)
+
+			_, err := callComparePeriods(rt, tt.args)
+			if err == nil {
+				t.Fatal("ComparePeriods() expected error but got none")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("ComparePeriods() error = %v, want error containing %q", err, tt.errMsg)
+			}
+			if len(*requestedURLs) != 0 {
+				t.Errorf("Expected no requests, got %d", len(*requestedURLs))
+			}
+		})
+	}
+}
+
+func TestReportsTool_ComparePeriods_OutputSchema(t *testing.T) {
+	t.Parallel()
+
+	rt, _ := newPeriodReportsTool(t, nil)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+
+	tool := &mcp.Tool{Name: "compare_periods", Description: tools.ComparePeriodsToolDescription}
+	mcp.AddTool(server, tool, rt.ComparePeriods)
+
+	if tool.OutputSchema == nil {
+		t.Fatal("compare_periods should declare an output schema")
+	}
+	for _, property := range []string{"current_period", "previous_period", "totals", "rows"} {
+		if _, ok := tool.OutputSchema.Properties[property]; !ok {
+			t.Errorf("OutputSchema is missing property %q", property)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+// Kinds of values that can be completed.
+const (
+	completionReport = "report"
+	completionMonth  = "month"
+	completionAgency = "agency"
+	completionDomain = "domain"
+)
+
+// maxCompletionValues is the maximum number of values in a completion result, as set by the MCP specification.
+const maxCompletionValues = 100
+
+// completionCacheTTL is how long the discovered agency and domain names are reused.
+const completionCacheTTL = time.Hour
+
+// discoveryLimit is the page size of the discovery requests, the largest the API accepts.
+const discoveryLimit = 10000
+
+// completionArguments maps the names of resource template variables and untyped prompt arguments to
+// the kind of value they take.
+var completionArguments = map[string]string{
+	"report_name": completionReport,
+	"report1":     completionReport,
+	"report2":     completionReport,
+	"month":       completionMonth,
+	"agency":      completionAgency,
+	"agency_name": completionAgency,
+	"domain":      completionDomain,
+}
+
+// discoveryReports maps the kinds of values discovered from the API to the report listing them and
+// the field of the report rows holding the value.
+var discoveryReports = map[string]struct {
+	report models.ReportType
+	field  func(*models.Reports) string
+}{
+	completionAgency: {report: models.ReportTypeAgencies, field: func(r *models.Reports) string { return r.ReportAgency }},
+	completionDomain: {report: models.ReportTypeDomains, field: func(r *models.Reports) string { return r.Domain }},
+}
+
+// Completer answers completion requests for prompt arguments and resource template variables with
+// report names, months, and the agency and domain names discovered from the agencies and domains reports.
+type Completer struct {
+	reports      *ReportsTool
+	argumentType func(prompt, argument string) string
+
+	mu         sync.Mutex
+	discovered map[string]discoveredValues
+}
+
+// discoveredValues are values discovered from the API and when they were fetched.
+type discoveredValues struct {
+	values    []string
+	fetchedAt time.Time
+}
+
+// NewCompleter creates a Completer. argumentType returns the declared type of a prompt argument, such
+// as "report", or an empty string, in which case the argument is completed by its name.
+func NewCompleter(reports *ReportsTool, argumentType func(prompt, argument string) string) *Completer {
+	return &Completer{
+		reports:      reports,
+		argumentType: argumentType,
+		discovered:   make(map[string]discoveredValues),
+	}
+}
+
+// Complete handles completion/complete requests. Values starting with the typed text, ignoring case,
+// are suggested; arguments that cannot be completed get no suggestions.
+func (c *Completer) Complete(ctx context.Context, _ *mcp.ServerSession,
+	params *mcp.CompleteParams) (*mcp.CompleteResult, error) {
+	if params.Ref == nil {
+		return nil, errors.New("invalid parameters: completion reference is required")
+	}
+
+	kind := completionArguments[params.Argument.Name]
+	if params.Ref.Type == "ref/prompt" {
+		if argType := c.argumentType(params.Ref.Name, params.Argument.Name); argType != "" {
+			kind = argType
+		}
+	}
+
+	candidates, err := c.candidates(ctx, kind)
+	if err != nil {
+		// Suggestions are optional, so a failed discovery leaves the client without them.
+		c.reports.logger.WarnContext(ctx, "Failed to discover completion values", "kind", kind, "error", err)
+	}
+
+	values := []string{}
+	prefix := strings.ToLower(params.Argument.Value)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), prefix) {
+			values = append(values, candidate)
+		}
+	}
+
+	c.reports.logger.DebugContext(ctx, "Completed argument", "ref", params.Ref.Type, "argument", params.Argument.Name,
+		"value", params.Argument.Value, "matches", len(values))
+
+	total := len(values)
+	if total > maxCompletionValues {
+		values = values[:maxCompletionValues]
+	}
+	return &mcp.CompleteResult{
+		Completion: mcp.CompletionResultDetails{
+			Values:  values,
+			Total:   total,
+			HasMore: total > len(values),
+		},
+	}, nil
+}
+
+// candidates returns all values of a kind.
+func (c *Completer) candidates(ctx context.Context, kind string) ([]string, error) {
+	switch kind {
+	case completionReport:
+		reportTypes := models.GetAllReportTypes()
+		names := make([]string, len(reportTypes))
+		for i, reportType := range reportTypes {
+			names[i] = reportType.String()
+		}
+		return names, nil
+	case completionMonth:
+		months := make([]string, 12)
+		for i := range months {
+			months[i] = fmt.Sprintf("%02d", i+1)
+		}
+		return months, nil
+	case completionAgency, completionDomain:
+		return c.discover(ctx, kind)
+	default:
+		return nil, nil
+	}
+}
+
+// discover returns the distinct, sorted values of a kind listed by its discovery report, fetching the
+// report at most once per completionCacheTTL. The lock is only held to read and store the values, so
+// a slow fetch does not hold up the completions of other kinds.
+func (c *Completer) discover(ctx context.Context, kind string) ([]string, error) {
+	now := c.reports.now()
+	if values, ok := c.cached(kind, now); ok {
+		return values, nil
+	}
+
+	values, err := c.fetchDiscovered(ctx, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Keep the values of a concurrent fetch that finished first
+	if cached, ok := c.discovered[kind]; ok && cached.fetchedAt.After(now) {
+		return cached.values, nil
+	}
+	c.discovered[kind] = discoveredValues{values: values, fetchedAt: now}
+	return values, nil
+}
+
+// cached returns the values of a kind if they were fetched less than completionCacheTTL before now.
+func (c *Completer) cached(kind string, now time.Time) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.discovered[kind]
+	if !ok || now.Sub(cached.fetchedAt) >= completionCacheTTL {
+		return nil, false
+	}
+	return cached.values, true
+}
+
+// fetchDiscovered fetches the discovery report of a kind and returns its distinct, sorted values.
+func (c *Completer) fetchDiscovered(ctx context.Context, kind string) ([]string, error) {
+	discovery := discoveryReports[kind]
+	request, err := c.reports.prepareRequest(models.ReportArgs{
+		ReportName: discovery.report.String(),
+		Limit:      discoveryLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	reports, _, err := c.reports.fetch(ctx, request, false, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s report: %w", discovery.report, err)
+	}
+
+	var values []string
+	for i := range reports {
+		if value := discovery.field(&reports[i]); value != "" {
+			values = append(values, value)
+		}
+	}
+	slices.Sort(values)
+	return slices.Compact(values), nil
+}
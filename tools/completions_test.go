@@ -0,0 +1,205 @@
+package tools_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/config"
+	"github.com/rameshsunkara/go-mcp-example/tools"
+)
+
+// newTestCompleter creates a Completer whose agencies and domains reports list a few names.
+func newTestCompleter(t *testing.T) (*tools.Completer, *[]string) {
+	t.Helper()
+
+	bodies := map[string]string{
+		"/reports/agencies/data": `[
+			{"id": 1, "date": "2024-01-02", "report_agency": "nasa", "visits": 10},
+			{"id": 2, "date": "2024-01-02", "report_agency": "national-archives", "visits": 20},
+			{"id": 3, "date": "2024-01-01", "report_agency": "nasa", "visits": 30},
+			{"id": 4, "date": "2024-01-01", "report_agency": "interior", "visits": 40}
+		]`,
+		"/reports/domains/data": `[
+			{"id": 1, "date": "2024-01-01", "domain": "nasa.gov", "visits": 10},
+			{"id": 2, "date": "2024-01-01", "domain": "usa.gov", "visits": 20}
+		]`,
+	}
+	var requestedURLs []string
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			requestedURLs = append(requestedURLs, req.URL.String())
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(bodies[req.URL.Path])),
+			}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	apiClient := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+	rt := tools.NewReportsTool(logger, &config.Config{}, apiClient)
+
+	argumentTypes := map[string]string{"monthly-report/month": "month", "compare-reports/report1": "report"}
+	completer := tools.NewCompleter(rt, func(prompt, argument string) string {
+		return argumentTypes[prompt+"/"+argument]
+	})
+
+	return completer, &requestedURLs
+}
+
+func TestCompleter_Complete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ref      *mcp.CompleteReference
+		argument string
+		value    string
+		expected []string
+	}{
+		{
+			name:     "typed prompt argument",
+			ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "compare-reports"},
+			argument: "report1",
+			value:    "tr",
+			expected: []string{"traffic", "traffic-sources"},
+		},
+		{
+			name:     "prompt argument completed by name",
+			ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "custom"},
+			argument: "report2",
+			value:    "DEV",
+			expected: []string{"devices"},
+		},
+		{
+			name:     "month",
+			ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "monthly-report"},
+			argument: "month",
+			value:    "1",
+			expected: []string{"10", "11", "12"},
+		},
+		{
+			name:     "resource template report",
+			ref:      &mcp.CompleteReference{Type: "ref/resource", URI: tools.ReportDataURITemplate},
+			argument: "report_name",
+			value:    "dom",
+			expected: []string{"domains"},
+		},
+		{
+			name:     "resource template agency",
+			ref:      &mcp.CompleteReference{Type: "ref/resource", URI: tools.AgencyReportDataURITemplate},
+			argument: "agency",
+			value:    "na",
+			expected: []string{"nasa", "national-archives"},
+		},
+		{
+			name:     "domain",
+			ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "custom"},
+			argument: "domain",
+			value:    "",
+			expected: []string{"nasa.gov", "usa.gov"},
+		},
+		{
+			name:     "free text argument",
+			ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "analyze-traffic"},
+			argument: "date_range",
+			value:    "last",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			completer, _ := newTestCompleter(t)
+			result, err := completer.Complete(context.Background(), nil, &mcp.CompleteParams{
+				Ref:      tt.ref,
+				Argument: mcp.CompleteParamsArgument{Name: tt.argument, Value: tt.value},
+			})
+			if err != nil {
+				t.Fatalf("Complete() unexpected error: %v", err)
+			}
+			if !slices.Equal(result.Completion.Values, tt.expected) {
+				t.Errorf("Complete() = %v, want %v", result.Completion.Values, tt.expected)
+			}
+			if result.Completion.Total != len(tt.expected) || result.Completion.HasMore {
+				t.Errorf("Complete() total = %d (has more %v), want %d", result.Completion.Total,
+					result.Completion.HasMore, len(tt.expected))
+			}
+		})
+	}
+}
+
+func TestCompleter_Complete_CachesDiscovery(t *testing.T) {
+	t.Parallel()
+
+	completer, requestedURLs := newTestCompleter(t)
+	params := &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: "ref/resource", URI: tools.AgencyReportDataURITemplate},
+		Argument: mcp.CompleteParamsArgument{Name: "agency", Value: "i"},
+	}
+
+	for range 3 {
+		result, err := completer.Complete(context.Background(), nil, params)
+		if err != nil {
+			t.Fatalf("Complete() unexpected error: %v", err)
+		}
+		if !slices.Equal(result.Completion.Values, []string{"interior"}) {
+			t.Errorf("Complete() = %v, want [interior]", result.Completion.Values)
+		}
+	}
+
+	want := "https://api.example.com/reports/agencies/data?limit=10000&page=1"
+	if len(*requestedURLs) != 1 || (*requestedURLs)[0] != want {
+		t.Errorf("Requested URLs = %v, want one request to %s", *requestedURLs, want)
+	}
+}
+
+func TestCompleter_Complete_DiscoversConcurrently(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			body := `[{"id": 1, "date": "2024-01-01", "domain": "nasa.gov", "visits": 10}]`
+			if req.URL.Path == "/reports/agencies/data" {
+				<-release
+				body = `[{"id": 1, "date": "2024-01-01", "report_agency": "nasa", "visits": 10}]`
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	apiClient := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+	completer := tools.NewCompleter(tools.NewReportsTool(logger, &config.Config{}, apiClient),
+		func(string, string) string { return "" })
+	complete := func(argument string) []string {
+		result, err := completer.Complete(context.Background(), nil, &mcp.CompleteParams{
+			Ref:      &mcp.CompleteReference{Type: "ref/resource", URI: tools.AgencyReportDataURITemplate},
+			Argument: mcp.CompleteParamsArgument{Name: argument, Value: "n"},
+		})
+		if err != nil {
+			t.Errorf("Complete() unexpected error: %v", err)
+			return nil
+		}
+		return result.Completion.Values
+	}
+
+	agencies := make(chan []string)
+	go func() { agencies <- complete("agency") }()
+
+	// The domains are discovered while the agencies report is still being fetched
+	if values := complete("domain"); !slices.Equal(values, []string{"nasa.gov"}) {
+		t.Errorf("Complete(domain) = %v, want [nasa.gov]", values)
+	}
+	close(release)
+	if values := <-agencies; !slices.Equal(values, []string{"nasa"}) {
+		t.Errorf("Complete(agency) = %v, want [nasa]", values)
+	}
+}
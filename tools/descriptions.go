@@ -4,8 +4,19 @@
 
 package tools
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
 // GetReportToolDescription contains the detailed description for the get_report tool.
-const GetReportToolDescription = `Fetch analytics reports from the Digital Analytics Program (DAP) API ` +
+// Its list of report types is generated from the report catalog.
+var GetReportToolDescription = fmt.Sprintf(getReportToolDescriptionFormat, reportTypeList())
+
+// getReportToolDescriptionFormat is the get_report tool description with a placeholder for the report types.
+const getReportToolDescriptionFormat = `Fetch analytics reports from the Digital Analytics Program (DAP) API ` +
 	`with optional filtering and pagination.
 
 The DAP provides analytics data for U.S. federal government websites. This tool allows you to ` +
@@ -15,36 +26,168 @@ PARAMETERS:
 - report_name (required): The type of report to fetch
 - limit (optional): Maximum number of records to return (1-10000, default 1000)
 - page (optional): Page number for pagination (default 1, 1-based indexing)
-- after (optional): Start date filter in YYYY-MM-DD format
-- before (optional): End date filter in YYYY-MM-DD format
+- after (optional): Start date filter in YYYY-MM-DD format or a date expression (resolves to its first day)
+- before (optional): End date filter in YYYY-MM-DD format or a date expression (resolves to its last day)
+- date_range (optional): Date expression covering the whole range; cannot be combined with after or before
+- agency_name (optional): Restrict results to a single agency (e.g. "general-services-administration")
+- domain (optional): Restrict results to a single domain (e.g. "nasa.gov"); cannot be combined with agency_name
+- fetch_all (optional): Walk pages automatically, starting at page, until all data is read or max_records is hit
+- max_records (optional): Record budget for multi-page fetches (1-100000, default 10000); implies fetch_all
+- filters (optional): Row predicates applied after fetching, all of which must match, e.g. ` +
+	`["country == United States", "visits > 1000"]. Operators: ==, !=, >, >=, <, <=, contains. Text ` +
+	`fields compare case-insensitively, metric fields numerically.
+- sort_by (optional): Field to sort the rows by, e.g. "visits", "users", "pageviews" or "date"
+- sort_order (optional): "desc" (default) or "asc"
+- top (optional): Keep only the first N rows after sorting; requires sort_by
+- fields (optional): Return only these fields of each row, plus id, report_name, report_agency and ` +
+	`date, e.g. ["device", "visits"]. Use it for large reports to keep the response small.
+- compact (optional): Return compact, non-indented JSON (default false)
+- output_format (optional): "json" (default), "csv", "markdown" or "ndjson". Tabular formats use the ` +
+	`requested fields as columns, or every field present in the rows in a fixed order.
+- embed_csv (optional): Also attach the rows as an embedded text/csv resource (default false)
+
+DATE EXPRESSIONS:
+"today", "yesterday", "last N days", "last N weeks", "last N months" (complete days or months before ` +
+	`today), "this month", "last month", "this year", "last year", "2024" (year), "2024-03" (month), ` +
+	`"2024-Q1" (quarter), and ranges such as "2024-01..2024-03" or "2024-03-01 to yesterday". ` +
+	`Explicit dates must be valid YYYY-MM-DD dates and after must not be later than before.
 
 AVAILABLE REPORT TYPES:
-- "devices": Device types used by visitors (desktop, mobile, tablet)
-- "browsers": Browser usage statistics (Chrome, Safari, Firefox, etc.)
-- "operating-systems": Operating system statistics (Windows, macOS, iOS, etc.)
-- "languages": Language preferences of visitors
-- "countries": Geographic breakdown by country
-- "cities": Geographic breakdown by city
-- "traffic": Traffic volume and trends over time
-- "top-pages": Most visited pages and their metrics
-- "downloads": File download statistics and popular downloads
-- "realtime": Real-time active user statistics
-- "traffic-sources": Traffic source analysis (direct, referral, search, etc.)
-- "domains": Analytics by domain for multi-domain agencies
-- "agencies": Analytics aggregated by government agency
+%s
+The dap://catalog/reports resource lists the dimension and metric fields of every report type.
 
 EXAMPLES:
 - get_report("devices") - Get device statistics with default settings
 - get_report("browsers", limit=50) - Get browser stats limited to 50 results
 - get_report("traffic", after="2024-01-01", before="2024-01-31") - Get traffic for January 2024
+- get_report("traffic", date_range="last 7 days") - Get traffic for the last seven complete days
+- get_report("devices", date_range="2024-Q1") - Get device stats for the first quarter of 2024
 - get_report("top-pages", page=2, limit=100) - Get second page of top pages (100 per page)
 - get_report("realtime") - Get current active users
+- get_report("devices", agency_name="national-aeronautics-space-administration") - Get device stats for NASA only
+- get_report("top-pages", domain="usa.gov") - Get the most visited pages on usa.gov
+- get_report("traffic", fetch_all=true, max_records=5000) - Get up to 5000 traffic records across pages
+- get_report("countries", sort_by="visits", top=10) - Get the ten countries with the most visits
+- get_report("top-pages", filters=["page contains /news", "pageviews > 1000"]) - Get busy news pages
+- get_report("traffic", fetch_all=true, fields=["date", "visits"], compact=true) - Get a compact daily series
+- get_report("browsers", output_format="markdown", fields=["browser", "visits"]) - Get a table for a document
+- get_report("devices", output_format="csv", embed_csv=true) - Get CSV ready to paste into a spreadsheet
 
 RESPONSE FORMAT:
 Returns JSON data containing analytics metrics. The response structure varies by report type but ` +
 	`typically includes numerical metrics (visits, users, pageviews), categorical data (device types, ` +
-	`browser names), time-series data, geographic information, and behavioral metrics.
+	`browser names), time-series data, geographic information, and behavioral metrics. Multi-page ` +
+	`fetches also include a "pagination" object with pages_read, records and truncated. The same data ` +
+	`is returned as structured content matching the tool's output schema.
 
 NOTE: This tool requires a valid API key to be configured via the API_KEY environment variable. ` +
 	`The API provides analytics data for U.S. federal government websites participating in the ` +
 	`Digital Analytics Program.`
+
+// reportTypeList renders the report catalog as one description line per report type.
+func reportTypeList() string {
+	var b strings.Builder
+	for _, report := range models.ReportCatalog() {
+		fmt.Fprintf(&b, "- %q: %s\n", report.Name, report.Description)
+	}
+	return b.String()
+}
+
+// AggregateReportToolDescription contains the detailed description for the aggregate_report tool.
+const AggregateReportToolDescription = `Fetch a Digital Analytics Program (DAP) report and summarize it ` +
+	`on the server, grouped by a dimension field.
+
+Use this tool instead of get_report when you need totals, averages or rankings: the rows are ` +
+	`aggregated server-side and only one compact row per group is returned, so there is no need to ` +
+	`add up raw records yourself.
+
+PARAMETERS:
+- report_name (required): The type of report to fetch (same values as get_report)
+- group_by (required): Field to group rows by, e.g. "device", "browser", "country", "date", "page", "source"
+- metrics (optional): Metric fields to summarize (default ["visits"]); one or more of ` +
+	`"visits", "users", "pageviews", "bounce_rate", "avg_session_duration", "active_visitors", ` +
+	`"total_events", "pageviews_per_session"
+- functions (optional): Aggregations to compute (default ["sum"]); one or more of "sum", "avg", ` +
+	`"min", "max", "count". The row count of each group is always included.
+- after, before, date_range (optional): Date filters, same format and expressions as get_report
+- agency_name, domain (optional): Restrict results to a single agency or domain
+- max_records (optional): Record budget for the fetch (1-100000, default 10000); all pages are read up to it
+
+EXAMPLES:
+- aggregate_report("devices", group_by="device") - Total visits per device type
+- aggregate_report("traffic", group_by="date", metrics=["visits", "users"], date_range="last 7 days") - ` +
+	`Daily visits and users for the last week
+- aggregate_report("browsers", group_by="browser", functions=["sum", "avg"]) - Total and average visits per browser
+
+RESPONSE FORMAT:
+Returns a Markdown table with one row per group, sorted by the first aggregated column in ` +
+	`descending order. Columns are named "<function>_<metric>", e.g. "sum_visits". The same table is ` +
+	`returned as structured content with report_name, group_by, columns, rows, records and truncated.`
+
+// ComparePeriodsToolDescription contains the detailed description for the compare_periods tool.
+const ComparePeriodsToolDescription = `Compare a Digital Analytics Program (DAP) report across two ` +
+	`periods, e.g. month-over-month or week-over-week.
+
+The tool fetches the same report for both periods, sums each metric per dimension value (device, ` +
+	`browser, page, ...), joins the two periods on that value and returns the absolute and percentage ` +
+	`change. Values seen in only one period are compared against zero.
+
+PARAMETERS:
+- report_name (required): The type of report to compare (same values as get_report)
+- period (required): The current period as a date expression, e.g. "last month", "2024-03", ` +
+	`"2024-Q1" or "last 7 days"
+- previous_period (optional): The period to compare against; defaults to the period of equal length ` +
+	`right before period (the previous month(s) for whole months)
+- dimension (optional): Field to join rows on; defaults to the report's natural dimension (device for ` +
+	`devices, browser for browsers, page for top-pages, ...). Reports without one, like traffic, are ` +
+	`compared as totals.
+- metrics (optional): Metric fields to compare (default ["visits"])
+- agency_name, domain (optional): Restrict results to a single agency or domain
+- max_records (optional): Record budget for each period (1-100000, default 10000)
+- top (optional): Only return the N rows with the largest change of the first metric
+
+EXAMPLES:
+- compare_periods("devices", period="last month") - Device visits last month versus the month before
+- compare_periods("traffic", period="2024-03", previous_period="2023-03", metrics=["visits", "users"]) - ` +
+	`Year-over-year traffic for March
+- compare_periods("top-pages", period="last 7 days", top=10) - The ten pages whose visits changed most ` +
+	`week-over-week
+
+RESPONSE FORMAT:
+Returns a Markdown table with a total row followed by one row per dimension value, sorted by the ` +
+	`absolute change of the first metric. Percentages are "n/a" when the previous value is zero. The same ` +
+	`data is returned as structured content with current_period, previous_period, totals and rows; each ` +
+	`period has a truncated flag that is true when its data exceeded max_records, and the text then ends ` +
+	`with a warning.`
+
+// DetectAnomaliesToolDescription contains the detailed description for the detect_anomalies tool.
+const DetectAnomaliesToolDescription = `Detect unusual days in a Digital Analytics Program (DAP) report, ` +
+	`e.g. sudden traffic spikes or drops.
+
+The tool fetches a date-keyed report (traffic works best; reports with several rows per day are summed ` +
+	`per day), builds a daily series of one metric and compares every day with the window of days right ` +
+	`before it. Days outside the expected band are flagged with their expected and actual values.
+
+PARAMETERS:
+- report_name (required): The report to analyze (same values as get_report), e.g. "traffic"
+- metric (optional): Metric to build the daily series from (default "visits")
+- method (optional): "zscore" flags days more than threshold standard deviations from the rolling ` +
+	`mean; "iqr" flags days more than threshold interquartile ranges outside the rolling quartiles ` +
+	`(default "zscore")
+- window (optional): Number of preceding days each day is compared against (default 7, minimum 2)
+- threshold (optional): Width of the band (default 3 for zscore, 1.5 for iqr)
+- after, before, date_range (optional): Period to analyze, as for get_report (default "last 30 days")
+- agency_name, domain (optional): Restrict results to a single agency or domain
+- max_records (optional): Record budget for the fetch (1-100000, default 10000)
+
+EXAMPLES:
+- detect_anomalies("traffic") - Unusual visit counts over the last 30 days
+- detect_anomalies("traffic", metric="users", method="iqr", date_range="last 3 months") - Robust ` +
+	`detection of unusual user counts
+- detect_anomalies("traffic", agency_name="nasa", window=14, threshold=2) - A more sensitive check for ` +
+	`one agency
+
+RESPONSE FORMAT:
+Returns a Markdown table of the flagged days with direction (spike or drop), actual value, expected ` +
+	`value, band and score. The first window days only serve as history. The same data is returned as ` +
+	`structured content.`
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+// Output formats supported by get_report.
+const (
+	OutputFormatJSON     = "json"
+	OutputFormatCSV      = "csv"
+	OutputFormatMarkdown = "markdown"
+	OutputFormatNDJSON   = "ndjson"
+)
+
+// validateOutputFormat checks that format is empty or one of the supported output formats.
+func validateOutputFormat(format string) error {
+	switch format {
+	case "", OutputFormatJSON, OutputFormatCSV, OutputFormatMarkdown, OutputFormatNDJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid output_format '%s', expected one of %s, %s, %s or %s", format,
+			OutputFormatJSON, OutputFormatCSV, OutputFormatMarkdown, OutputFormatNDJSON)
+	}
+}
+
+// renderReport renders the response in the given output format. Tabular formats use the
+// requested fields as columns, or the fields present in the rows when none were requested.
+func renderReport(response models.ReportResponse, fields []string, format string, compact bool) (string, error) {
+	columns := fields
+	if len(columns) == 0 {
+		columns = models.PresentFields(response.Data)
+	}
+
+	switch format {
+	case OutputFormatCSV:
+		return renderCSV(response.Data, columns)
+	case OutputFormatMarkdown:
+		return renderMarkdown(response.Data, columns), nil
+	case OutputFormatNDJSON:
+		return renderNDJSON(response.Data, columns)
+	default:
+		body, err := marshalReportResponse(response, compact)
+		return string(body), err
+	}
+}
+
+// marshalReportResponse renders the response as JSON, indented unless compact output was requested.
+// Its rows are the structured content of the result, so both hold the same fields.
+func marshalReportResponse(response models.ReportResponse, compact bool) ([]byte, error) {
+	if compact {
+		return json.Marshal(response)
+	}
+	return json.MarshalIndent(response, "", "  ")
+}
+
+// renderCSV renders the rows as CSV with a header line.
+func renderCSV(rows []models.Reports, columns []string) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(columns); err != nil {
+		return "", fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for i := range rows {
+		if err := w.Write(rowCells(&rows[i], columns)); err != nil {
+			return "", fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// renderMarkdown renders the rows as a Markdown table.
+func renderMarkdown(rows []models.Reports, columns []string) string {
+	table := make([][]string, len(rows))
+	for i := range rows {
+		table[i] = rowCells(&rows[i], columns)
+	}
+	return markdownTable(columns, table)
+}
+
+// renderNDJSON renders the rows as newline-delimited JSON, one object per row.
+func renderNDJSON(rows []models.Reports, columns []string) (string, error) {
+	var b strings.Builder
+	for i := range rows {
+		line, err := json.Marshal(rows[i].Values(columns))
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal row: %w", err)
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// rowCells returns the values of the given columns of a row as text.
+func rowCells(row *models.Reports, columns []string) []string {
+	values := row.Values(columns)
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		switch value := values[column].(type) {
+		case string:
+			cells[i] = value
+		case int:
+			cells[i] = strconv.Itoa(value)
+		case float64:
+			cells[i] = strconv.FormatFloat(value, 'f', -1, 64)
+		default:
+			cells[i] = fmt.Sprint(value)
+		}
+	}
+	return cells
+}
+
+// markdownTable renders a header and rows as a Markdown table, escaping pipes in cells.
+func markdownTable(header []string, rows [][]string) string {
+	var b strings.Builder
+
+	b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	b.WriteString(strings.Repeat("|---", len(header)) + "|\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
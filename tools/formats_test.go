@@ -0,0 +1,127 @@
+package tools_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+func TestReportsTool_GetReport_OutputFormats(t *testing.T) {
+	t.Parallel()
+
+	body := `[
+		{"id": 1, "report_name": "pages", "report_agency": "gsa", "date": "2024-01-01",
+			"page_title": "News, \"Updates\"", "bounce_rate": 0.25, "visits": 100},
+		{"id": 2, "report_name": "pages", "report_agency": "gsa", "date": "2024-01-01",
+			"page_title": "A | B", "visits": 50}
+	]`
+
+	tests := []struct {
+		name     string
+		args     models.ReportArgs
+		expected string
+	}{
+		{
+			name: "csv with present fields in declaration order",
+			args: models.ReportArgs{ReportName: "top-pages", OutputFormat: "csv"},
+			expected: "id,report_name,report_agency,date,bounce_rate,page_title,visits\n" +
+				"1,pages,gsa,2024-01-01,0.25,\"News, \"\"Updates\"\"\",100\n" +
+				"2,pages,gsa,2024-01-01,0,A | B,50\n",
+		},
+		{
+			name: "csv with requested fields",
+			args: models.ReportArgs{ReportName: "top-pages", OutputFormat: "csv", Fields: []string{"visits", "date"}},
+			expected: "visits,date\n" +
+				"100,2024-01-01\n" +
+				"50,2024-01-01\n",
+		},
+		{
+			name: "markdown",
+			args: models.ReportArgs{ReportName: "top-pages", OutputFormat: "markdown", Fields: []string{"page_title", "visits"}},
+			expected: "| page_title | visits |\n" +
+				"|---|---|\n" +
+				"| News, \"Updates\" | 100 |\n" +
+				"| A \\| B | 50 |",
+		},
+		{
+			name: "ndjson",
+			args: models.ReportArgs{ReportName: "top-pages", OutputFormat: "ndjson", Fields: []string{"id", "visits"}},
+			expected: "{\"id\":1,\"visits\":100}\n" +
+				"{\"id\":2,\"visits\":50}\n",
+		},
+		{
+			name: "json",
+			args: models.ReportArgs{ReportName: "top-pages", OutputFormat: "json", Compact: true, Fields: []string{"id"}},
+			expected: `{"data":[{"id":1,"report_name":"pages","report_agency":"gsa","date":"2024-01-01"},` +
+				`{"id":2,"report_name":"pages","report_agency":"gsa","date":"2024-01-01"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt, _ := newTestReportsTool(t, body, http.StatusOK)
+
+			result, err := callGetReport(rt, tt.args)
+			if err != nil {
+				t.Fatalf("GetReport() unexpected error: %v", err)
+			}
+
+			text := result.Content[0].(*mcp.TextContent).Text
+			if !strings.Contains(text, tt.expected) {
+				t.Errorf("Result text missing:\n%s\ngot:\n%s", tt.expected, text)
+			}
+			if len(result.Content) != 1 {
+				t.Errorf("Content length = %d, want 1 without embed_csv", len(result.Content))
+			}
+		})
+	}
+}
+
+func TestReportsTool_GetReport_EmbedCSV(t *testing.T) {
+	t.Parallel()
+
+	rt, _ := newTestReportsTool(t, sampleReportsJSON, http.StatusOK)
+
+	result, err := callGetReport(rt, models.ReportArgs{
+		ReportName: "devices",
+		Fields:     []string{"device", "visits"},
+		EmbedCSV:   true,
+	})
+	if err != nil {
+		t.Fatalf("GetReport() unexpected error: %v", err)
+	}
+
+	if len(result.Content) != 2 {
+		t.Fatalf("Content length = %d, want text and embedded resource", len(result.Content))
+	}
+	resource, ok := result.Content[1].(*mcp.EmbeddedResource)
+	if !ok {
+		t.Fatalf("Content[1] = %T, want *mcp.EmbeddedResource", result.Content[1])
+	}
+	if resource.Resource.MIMEType != "text/csv" || resource.Resource.URI != "dap://reports/devices/data.csv" {
+		t.Errorf("Resource = %s (%s), want dap://reports/devices/data.csv (text/csv)",
+			resource.Resource.URI, resource.Resource.MIMEType)
+	}
+	if expected := "device,visits\ndesktop,100\nmobile,50\n"; resource.Resource.Text != expected {
+		t.Errorf("Resource text = %q, want %q", resource.Resource.Text, expected)
+	}
+}
+
+func TestReportsTool_GetReport_InvalidOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	rt, requestedURLs := newTestReportsTool(t, sampleReportsJSON, http.StatusOK)
+
+	_, err := callGetReport(rt, models.ReportArgs{ReportName: "devices", OutputFormat: "xml"})
+	if err == nil || !strings.Contains(err.Error(), "invalid output_format 'xml'") {
+		t.Errorf("GetReport() error = %v, want invalid output_format error", err)
+	}
+	if len(*requestedURLs) != 0 {
+		t.Errorf("Expected no requests, got %d", len(*requestedURLs))
+	}
+}
@@ -0,0 +1,213 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/config"
+)
+
+const (
+	// lowQuotaFraction is the share of the quota below which it is considered low.
+	lowQuotaFraction = 0.1
+	// lowQuotaRequests is the remaining request count considered low when the limit is unknown.
+	lowQuotaRequests = 10
+	// exhaustedProbeInterval is how long requests are refused after the API reported an
+	// exhausted quota, before a single request is let through to check whether it recovered.
+	exhaustedProbeInterval = time.Minute
+)
+
+// ErrQuotaExhausted is returned when no API requests remain in the current quota window.
+var ErrQuotaExhausted = errors.New("API quota exhausted")
+
+// Quota is a snapshot of the API request quota.
+type Quota struct {
+	Limit     int  // Requests allowed per hour, 0 if unknown
+	Remaining int  // Requests left in the current hour
+	Known     bool // Whether any quota information is available
+}
+
+// Low reports whether the remaining quota is about to run out.
+func (q Quota) Low() bool {
+	if !q.Known {
+		return false
+	}
+	if q.Limit > 0 {
+		return float64(q.Remaining) <= float64(q.Limit)*lowQuotaFraction
+	}
+	return q.Remaining <= lowQuotaRequests
+}
+
+// RateLimiter is a token-bucket limiter with an optional hourly ceiling that also tracks
+// the quota reported by the API through X-RateLimit-* response headers.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	// Token bucket
+	rate   float64 // Tokens added per second, 0 disables the bucket
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// Hourly ceiling, enforced over a sliding window
+	hourlyLimit int
+	sent        []time.Time
+
+	// Quota reported by the upstream API
+	upstreamLimit     int
+	upstreamRemaining int
+	upstreamKnown     bool
+	upstreamSeenAt    time.Time
+
+	now func() time.Time
+}
+
+// NewRateLimiter creates a RateLimiter allowing requestsPerSecond with the given burst and at
+// most hourlyLimit requests per hour. Zero values disable the respective limit.
+func NewRateLimiter(requestsPerSecond float64, burst, hourlyLimit int) *RateLimiter {
+	burst = max(burst, 1)
+	return &RateLimiter{
+		rate:        requestsPerSecond,
+		burst:       float64(burst),
+		tokens:      float64(burst),
+		hourlyLimit: hourlyLimit,
+		now:         time.Now,
+	}
+}
+
+// NewRateLimiterFromConfig creates the limiter described by the configuration.
+// It returns nil when all limits are disabled.
+func NewRateLimiterFromConfig(cfg *config.Config) *RateLimiter {
+	if cfg.RateLimitRPS <= 0 && cfg.RateLimitHourly <= 0 {
+		return nil
+	}
+	return NewRateLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst, cfg.RateLimitHourly)
+}
+
+// Wait blocks until a request may be sent. It fails immediately when the hourly ceiling or
+// the upstream quota is exhausted, or when the wait would outlive the context deadline.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := l.now()
+
+	if l.upstreamKnown && l.upstreamRemaining <= 0 && now.Sub(l.upstreamSeenAt) < exhaustedProbeInterval {
+		l.mu.Unlock()
+		return fmt.Errorf("%w: the API reported no remaining requests", ErrQuotaExhausted)
+	}
+
+	l.pruneSent(now)
+	if l.hourlyLimit > 0 && len(l.sent) >= l.hourlyLimit {
+		resetIn := l.sent[0].Add(time.Hour).Sub(now)
+		l.mu.Unlock()
+		return fmt.Errorf("%w: hourly limit of %d requests reached, resets in %s",
+			ErrQuotaExhausted, l.hourlyLimit, resetIn.Round(time.Second))
+	}
+
+	// Reserve a token, possibly going into debt, and wait for the debt to be repaid
+	var delay time.Duration
+	if l.rate > 0 {
+		l.refill(now)
+		l.tokens--
+		if l.tokens < 0 {
+			delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+		}
+	}
+	sendAt := now.Add(delay)
+	l.sent = append(l.sent, sendAt)
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && sendAt.After(deadline) {
+		l.cancelReservation(sendAt)
+		return fmt.Errorf("rate limit wait of %s exceeds request deadline: %w", delay, context.DeadlineExceeded)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.cancelReservation(sendAt)
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Observe records the quota reported in the API response headers.
+func (l *RateLimiter) Observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.upstreamKnown = true
+	l.upstreamRemaining = remaining
+	l.upstreamSeenAt = l.now()
+	if limit, limitErr := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit")); limitErr == nil {
+		l.upstreamLimit = limit
+	}
+}
+
+// Quota returns the tightest of the upstream-reported quota and the local hourly ceiling.
+func (l *RateLimiter) Quota() Quota {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var quota Quota
+	if l.upstreamKnown {
+		quota = Quota{Limit: l.upstreamLimit, Remaining: l.upstreamRemaining, Known: true}
+	}
+
+	if l.hourlyLimit > 0 {
+		l.pruneSent(l.now())
+		localRemaining := l.hourlyLimit - len(l.sent)
+		if !quota.Known || localRemaining < quota.Remaining {
+			quota = Quota{Limit: l.hourlyLimit, Remaining: localRemaining, Known: true}
+		}
+	}
+
+	return quota
+}
+
+// refill adds the tokens accumulated since the last refill. Callers must hold l.mu.
+func (l *RateLimiter) refill(now time.Time) {
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+}
+
+// pruneSent drops requests older than an hour from the sliding window. Callers must hold l.mu.
+func (l *RateLimiter) pruneSent(now time.Time) {
+	cutoff := now.Add(-time.Hour)
+	i := 0
+	for i < len(l.sent) && !l.sent[i].After(cutoff) {
+		i++
+	}
+	l.sent = l.sent[i:]
+}
+
+// cancelReservation returns a token and hourly slot reserved by Wait that were not used.
+func (l *RateLimiter) cancelReservation(sendAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.burst, l.tokens+1)
+	if i := slices.Index(l.sent, sendAt); i >= 0 {
+		l.sent = slices.Delete(l.sent, i, i+1)
+	}
+}
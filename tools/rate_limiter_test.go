@@ -0,0 +1,238 @@
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/config"
+	"github.com/rameshsunkara/go-mcp-example/models"
+	"github.com/rameshsunkara/go-mcp-example/tools"
+)
+
+// quotaResponse creates a successful API response carrying rate limit headers.
+func quotaResponse(limit, remaining string) *http.Response {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(sampleReportsJSON)),
+	}
+	resp.Header.Set("X-RateLimit-Limit", limit)
+	resp.Header.Set("X-RateLimit-Remaining", remaining)
+	return resp
+}
+
+func TestRateLimiter_Burst(t *testing.T) {
+	t.Parallel()
+
+	limiter := tools.NewRateLimiter(20, 3, 0)
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 3 {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait() unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("Burst requests took %v, should not wait", elapsed)
+	}
+
+	// The fourth request has to wait for a token to be refilled (1/20s)
+	start = time.Now()
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatalf("Wait() unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("Request beyond burst took %v, should wait for a token", elapsed)
+	}
+}
+
+func TestRateLimiter_HourlyCeiling(t *testing.T) {
+	t.Parallel()
+
+	limiter := tools.NewRateLimiter(0, 0, 2)
+	ctx := context.Background()
+
+	for range 2 {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait() unexpected error: %v", err)
+		}
+	}
+
+	err := limiter.Wait(ctx)
+	if !errors.Is(err, tools.ErrQuotaExhausted) {
+		t.Errorf("Wait() error = %v, want ErrQuotaExhausted", err)
+	}
+
+	quota := limiter.Quota()
+	if !quota.Known || quota.Remaining != 0 || quota.Limit != 2 {
+		t.Errorf("Quota() = %+v, want 0 of 2 remaining", quota)
+	}
+}
+
+func TestRateLimiter_RespectsDeadline(t *testing.T) {
+	t.Parallel()
+
+	limiter := tools.NewRateLimiter(1, 1, 0)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := limiter.Wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("Wait() took %v, should fail fast when the wait exceeds the deadline", elapsed)
+	}
+}
+
+func TestRateLimiter_UpstreamQuota(t *testing.T) {
+	t.Parallel()
+
+	limiter := tools.NewRateLimiter(0, 0, 0)
+
+	resp := quotaResponse("1000", "50")
+	defer resp.Body.Close()
+	limiter.Observe(resp)
+
+	quota := limiter.Quota()
+	if quota != (tools.Quota{Limit: 1000, Remaining: 50, Known: true}) {
+		t.Errorf("Quota() = %+v, want 50 of 1000 remaining", quota)
+	}
+	if !quota.Low() {
+		t.Error("Quota with 5% remaining should be low")
+	}
+
+	exhausted := quotaResponse("1000", "0")
+	defer exhausted.Body.Close()
+	limiter.Observe(exhausted)
+
+	if err := limiter.Wait(context.Background()); !errors.Is(err, tools.ErrQuotaExhausted) {
+		t.Errorf("Wait() error = %v, want ErrQuotaExhausted", err)
+	}
+}
+
+func TestQuota_Low(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		quota    tools.Quota
+		expected bool
+	}{
+		{name: "unknown quota", quota: tools.Quota{}, expected: false},
+		{name: "plenty remaining", quota: tools.Quota{Limit: 1000, Remaining: 500, Known: true}, expected: false},
+		{name: "ten percent remaining", quota: tools.Quota{Limit: 1000, Remaining: 100, Known: true}, expected: true},
+		{name: "unknown limit, few remaining", quota: tools.Quota{Remaining: 5, Known: true}, expected: true},
+		{name: "unknown limit, many remaining", quota: tools.Quota{Remaining: 500, Known: true}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.quota.Low(); got != tt.expected {
+				t.Errorf("Low() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewRateLimiterFromConfig(t *testing.T) {
+	t.Parallel()
+
+	if limiter := tools.NewRateLimiterFromConfig(&config.Config{}); limiter != nil {
+		t.Error("NewRateLimiterFromConfig() should return nil when all limits are disabled")
+	}
+	if limiter := tools.NewRateLimiterFromConfig(&config.Config{RateLimitHourly: 10}); limiter == nil {
+		t.Error("NewRateLimiterFromConfig() should return a limiter when a limit is configured")
+	}
+}
+
+func TestAPIClient_DoRequest_RateLimited(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	mockClient := &MockHTTPClient{
+		DoFunc: func(_ *http.Request) (*http.Response, error) {
+			requests++
+			return quotaResponse("1000", "999"), nil
+		},
+	}
+	client := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+	client.Limiter = tools.NewRateLimiter(0, 0, 1)
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/test", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	resp, err := client.DoRequest(req)
+	if err != nil {
+		t.Fatalf("DoRequest() unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err = client.DoRequest(req); !errors.Is(err, tools.ErrQuotaExhausted) {
+		t.Errorf("DoRequest() error = %v, want ErrQuotaExhausted", err)
+	}
+	if requests != 1 {
+		t.Errorf("Requests = %d, want 1", requests)
+	}
+
+	// The local hourly ceiling is tighter than the upstream quota
+	if quota := client.Quota(); quota.Remaining != 0 || quota.Limit != 1 {
+		t.Errorf("Quota() = %+v, want 0 of 1 remaining", quota)
+	}
+}
+
+func TestReportsTool_GetReport_LowQuotaWarning(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		remaining     string
+		expectWarning bool
+	}{
+		{name: "low quota", remaining: "3", expectWarning: true},
+		{name: "healthy quota", remaining: "900", expectWarning: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockClient := &MockHTTPClient{
+				DoFunc: func(_ *http.Request) (*http.Response, error) {
+					return quotaResponse("1000", tt.remaining), nil
+				},
+			}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			apiClient := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+			apiClient.Limiter = tools.NewRateLimiter(0, 0, 0)
+			rt := tools.NewReportsTool(logger, &config.Config{}, apiClient)
+
+			result, err := callGetReport(rt, models.ReportArgs{ReportName: "devices"})
+			if err != nil {
+				t.Fatalf("GetReport() unexpected error: %v", err)
+			}
+
+			text := result.Content[0].(*mcp.TextContent).Text
+			if strings.Contains(text, "WARNING: Only 3 of 1000 API requests remain") != tt.expectWarning {
+				t.Errorf("Quota warning present = %v, want %v in:\n%s", !tt.expectWarning, tt.expectWarning, text)
+			}
+		})
+	}
+}
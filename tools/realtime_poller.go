@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+// RealtimeResourceURI is the URI of the live realtime report resource clients can subscribe to.
+const RealtimeResourceURI = "dap://reports/realtime"
+
+// ResourceNotifier delivers resource-updated notifications to the clients subscribed to a resource.
+type ResourceNotifier interface {
+	Subscribed(uri string) bool
+	NotifyUpdated(ctx context.Context, uri string)
+}
+
+// RealtimePoller re-fetches the realtime report on an interval while clients are subscribed to it
+// and notifies them when the active visitor counts change.
+type RealtimePoller struct {
+	reports  *ReportsTool
+	notifier ResourceNotifier
+	interval time.Duration
+
+	// last holds the active visitor counts of the previous poll, nil before the first one.
+	last []int
+}
+
+// NewRealtimePoller creates a RealtimePoller that polls the realtime report every interval.
+func NewRealtimePoller(reports *ReportsTool, notifier ResourceNotifier, interval time.Duration) *RealtimePoller {
+	return &RealtimePoller{
+		reports:  reports,
+		notifier: notifier,
+		interval: interval,
+	}
+}
+
+// Run polls until the context is canceled. Failed polls are logged and retried on the next tick.
+func (p *RealtimePoller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.reports.logger.InfoContext(ctx, "Realtime poller started", "interval", p.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			p.reports.logger.InfoContext(ctx, "Realtime poller stopped")
+			return
+		case <-ticker.C:
+			if _, err := p.Poll(ctx); err != nil {
+				p.reports.logger.WarnContext(ctx, "Realtime poll failed", "error", err)
+			}
+		}
+	}
+}
+
+// Poll fetches the realtime report once, if any client is subscribed to it, and notifies the
+// subscribers when the active visitor counts differ from the previous poll. The first poll only
+// records the counts. Poll reports whether a notification was sent and must not be called
+// concurrently.
+func (p *RealtimePoller) Poll(ctx context.Context) (bool, error) {
+	if !p.notifier.Subscribed(RealtimeResourceURI) {
+		return false, nil
+	}
+
+	request, err := p.reports.prepareRequest(models.ReportArgs{ReportName: models.ReportTypeActiveUsers.String()})
+	if err != nil {
+		return false, err
+	}
+	// Bypass the cache, whose realtime TTL can hide changes between polls
+	reports, err := p.reports.refreshReportPage(ctx, request)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch realtime report: %w", err)
+	}
+
+	counts := make([]int, len(reports))
+	for i := range reports {
+		counts[i] = reports[i].ActiveVisitors
+	}
+
+	changed := p.last != nil && !slices.Equal(counts, p.last)
+	p.last = counts
+	if changed {
+		p.reports.logger.InfoContext(ctx, "Realtime active visitors changed", "rows", len(counts))
+		p.notifier.NotifyUpdated(ctx, RealtimeResourceURI)
+	}
+	return changed, nil
+}
@@ -0,0 +1,133 @@
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/config"
+	"github.com/rameshsunkara/go-mcp-example/tools"
+)
+
+// fakeNotifier records resource-updated notifications.
+type fakeNotifier struct {
+	subscribed bool
+	updated    []string
+}
+
+func (n *fakeNotifier) Subscribed(string) bool { return n.subscribed }
+
+func (n *fakeNotifier) NotifyUpdated(_ context.Context, uri string) {
+	n.updated = append(n.updated, uri)
+}
+
+func TestRealtimePoller_Poll(t *testing.T) {
+	t.Parallel()
+
+	// Active visitor counts returned by consecutive realtime report requests.
+	counts := []int{120, 120, 135}
+	var requestedURLs []string
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			count := counts[len(requestedURLs)]
+			requestedURLs = append(requestedURLs, req.URL.String())
+			body := fmt.Sprintf(`[{"id": 1, "date": "2024-01-15", "hour": "10", "active_visitors": %d}]`, count)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	apiClient := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+	rt := tools.NewReportsTool(logger, &config.Config{}, apiClient)
+
+	notifier := &fakeNotifier{}
+	poller := tools.NewRealtimePoller(rt, notifier, 0)
+	ctx := context.Background()
+
+	steps := []struct {
+		name       string
+		subscribed bool
+		changed    bool
+		requests   int
+	}{
+		{name: "no subscribers", subscribed: false, changed: false, requests: 0},
+		{name: "first poll records counts", subscribed: true, changed: false, requests: 1},
+		{name: "unchanged counts", subscribed: true, changed: false, requests: 2},
+		{name: "changed counts", subscribed: true, changed: true, requests: 3},
+	}
+
+	for _, step := range steps {
+		notifier.subscribed = step.subscribed
+		changed, err := poller.Poll(ctx)
+		if err != nil {
+			t.Fatalf("%s: Poll() unexpected error: %v", step.name, err)
+		}
+		if changed != step.changed {
+			t.Errorf("%s: Poll() = %v, want %v", step.name, changed, step.changed)
+		}
+		if len(requestedURLs) != step.requests {
+			t.Errorf("%s: %d requests, want %d", step.name, len(requestedURLs), step.requests)
+		}
+	}
+
+	if len(notifier.updated) != 1 || notifier.updated[0] != tools.RealtimeResourceURI {
+		t.Errorf("Notifications = %v, want one for %s", notifier.updated, tools.RealtimeResourceURI)
+	}
+	if want := "https://api.example.com/reports/realtime/data?limit=1000&page=1"; requestedURLs[0] != want {
+		t.Errorf("Requested URL = %s, want %s", requestedURLs[0], want)
+	}
+}
+
+func TestRealtimePoller_Poll_BypassesCache(t *testing.T) {
+	t.Parallel()
+
+	// The upstream count changes while the cached realtime report is still fresh.
+	count := 120
+	requests := 0
+	mockClient := &MockHTTPClient{
+		DoFunc: func(*http.Request) (*http.Response, error) {
+			requests++
+			body := fmt.Sprintf(`[{"id": 1, "date": "2024-01-15", "hour": "10", "active_visitors": %d}]`, count)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	apiClient := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+	apiClient.Cache = tools.NewMemoryCache(10)
+	rt := tools.NewReportsTool(logger, &config.Config{CacheRealtimeTTL: time.Hour}, apiClient)
+
+	notifier := &fakeNotifier{subscribed: true}
+	poller := tools.NewRealtimePoller(rt, notifier, 0)
+	ctx := context.Background()
+
+	if _, err := poller.Poll(ctx); err != nil {
+		t.Fatalf("Poll() unexpected error: %v", err)
+	}
+	count = 135
+	changed, err := poller.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll() unexpected error: %v", err)
+	}
+	if !changed || len(notifier.updated) != 1 {
+		t.Errorf("Poll() = %v with notifications %v, want a notification for the change", changed, notifier.updated)
+	}
+	if requests != 2 {
+		t.Errorf("%d requests, want 2", requests)
+	}
+
+	// Reads after the notification see the fresh report
+	body, ok := apiClient.Cache.Get(tools.CacheKey("https://api.example.com/reports/realtime/data?limit=1000&page=1"))
+	if !ok || !strings.Contains(string(body), `"active_visitors": 135`) {
+		t.Errorf("Cached realtime report = %s, want the polled one", body)
+	}
+}
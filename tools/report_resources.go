@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/models"
+)
+
+// URI templates of the report data resources.
+const (
+	ReportDataURITemplate       = "dap://reports/{report_name}{?after,before,limit}"
+	AgencyReportDataURITemplate = "dap://agencies/{agency}/reports/{report_name}{?after,before,limit}"
+)
+
+// ReadReportResource serves report data for the report resource templates. It fetches a single
+// page through the same path as get_report and returns the rows as JSON.
+func (rt *ReportsTool) ReadReportResource(ctx context.Context, _ *mcp.ServerSession,
+	params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+	rt.logger.InfoContext(ctx, "Processing report resource request", "uri", params.URI)
+
+	args, query, err := parseReportResourceURI(params.URI)
+	if err != nil {
+		return nil, mcp.ResourceNotFoundError(params.URI)
+	}
+	if !models.ReportType(args.ReportName).IsValid() {
+		rt.logger.ErrorContext(ctx, "Report type not found", "report_name", args.ReportName)
+		return nil, mcp.ResourceNotFoundError(params.URI)
+	}
+
+	args.After = query.Get("after")
+	args.Before = query.Get("before")
+	if limit := query.Get("limit"); limit != "" {
+		if args.Limit, err = strconv.Atoi(limit); err != nil {
+			return nil, fmt.Errorf("invalid parameters: limit must be a number, got '%s'", limit)
+		}
+	}
+
+	request, err := rt.prepareRequest(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	reports, _, err := rt.fetch(ctx, request, false, 0)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	body, err := marshalReportResponse(models.ReportResponse{Data: reports}, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render response: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: params.URI, MIMEType: "application/json", Text: string(body)},
+		},
+	}, nil
+}
+
+// parseReportResourceURI extracts the report and agency from a report data resource URI of the
+// form dap://reports/{report_name} or dap://agencies/{agency}/reports/{report_name}, and returns
+// its query parameters.
+func parseReportResourceURI(uri string) (models.ReportArgs, url.Values, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return models.ReportArgs{}, nil, err
+	}
+	if u.Scheme != "dap" {
+		return models.ReportArgs{}, nil, fmt.Errorf("wrong scheme: %q", u.Scheme)
+	}
+
+	var args models.ReportArgs
+	segments := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	switch {
+	case u.Host == "reports" && len(segments) == 1:
+		args.ReportName = segments[0]
+	case u.Host == "agencies" && len(segments) == 3 && segments[1] == "reports" && segments[0] != "":
+		args.AgencyName = segments[0]
+		args.ReportName = segments[2]
+	default:
+		return models.ReportArgs{}, nil, fmt.Errorf("not a report resource: %s", uri)
+	}
+
+	return args, u.Query(), nil
+}
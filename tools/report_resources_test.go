@@ -0,0 +1,160 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/models"
+	"github.com/rameshsunkara/go-mcp-example/resources"
+	"github.com/rameshsunkara/go-mcp-example/tools"
+)
+
+func TestReportsTool_ReadReportResource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		uri         string
+		expectedURL string
+	}{
+		{
+			name:        "report with query",
+			uri:         "dap://reports/devices?after=2024-01-01&before=2024-01-31&limit=50",
+			expectedURL: "https://api.example.com/reports/devices/data?after=2024-01-01&before=2024-01-31&limit=50&page=1",
+		},
+		{
+			name:        "report without query",
+			uri:         "dap://reports/traffic",
+			expectedURL: "https://api.example.com/reports/traffic/data?limit=1000&page=1",
+		},
+		{
+			name:        "agency report",
+			uri:         "dap://agencies/nasa/reports/browsers?limit=10",
+			expectedURL: "https://api.example.com/agencies/nasa/reports/browsers/data?limit=10&page=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt, requestedURLs := newTestReportsTool(t, deviceReportsJSON, http.StatusOK)
+
+			result, err := rt.ReadReportResource(context.Background(), nil, &mcp.ReadResourceParams{URI: tt.uri})
+			if err != nil {
+				t.Fatalf("ReadReportResource(%q) unexpected error: %v", tt.uri, err)
+			}
+
+			if len(*requestedURLs) != 1 || (*requestedURLs)[0] != tt.expectedURL {
+				t.Errorf("Requested URLs = %v, want [%s]", *requestedURLs, tt.expectedURL)
+			}
+
+			contents := result.Contents[0]
+			if contents.URI != tt.uri || contents.MIMEType != "application/json" {
+				t.Errorf("Contents = %s (%s), want %s (application/json)", contents.URI, contents.MIMEType, tt.uri)
+			}
+			var response models.ReportResponse
+			if err = json.Unmarshal([]byte(contents.Text), &response); err != nil {
+				t.Fatalf("Contents are not a report response: %v", err)
+			}
+			if len(response.Data) == 0 {
+				t.Error("Expected report rows in the resource contents")
+			}
+		})
+	}
+}
+
+func TestReportsTool_ReadReportResource_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		uri    string
+		errMsg string
+	}{
+		{name: "unknown report", uri: "dap://reports/weather", errMsg: "Resource not found"},
+		{name: "wrong scheme", uri: "https://reports/devices", errMsg: "Resource not found"},
+		{name: "malformed agency path", uri: "dap://agencies/nasa/devices", errMsg: "Resource not found"},
+		{name: "non-numeric limit", uri: "dap://reports/devices?limit=ten", errMsg: "limit must be a number"},
+		{name: "invalid date", uri: "dap://reports/devices?after=someday", errMsg: "invalid parameters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt, requestedURLs := newTestReportsTool(t, deviceReportsJSON, http.StatusOK)
+
+			_, err := rt.ReadReportResource(context.Background(), nil, &mcp.ReadResourceParams{URI: tt.uri})
+			if err == nil {
+				t.Fatalf("ReadReportResource(%q) expected error but got none", tt.uri)
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("ReadReportResource(%q) error = %v, want error containing %q", tt.uri, err, tt.errMsg)
+			}
+			if len(*requestedURLs) != 0 {
+				t.Errorf("Expected no requests, got %d", len(*requestedURLs))
+			}
+		})
+	}
+}
+
+func TestReportsTool_ReadReportResource_Template(t *testing.T) {
+	t.Parallel()
+
+	rt, requestedURLs := newTestReportsTool(t, deviceReportsJSON, http.StatusOK)
+
+	// The catalog entry of a report is registered next to the template, as in main
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	server.AddResource(&mcp.Resource{
+		Name:     "report-devices",
+		MIMEType: "application/json",
+		URI:      resources.ReportResourceURI(models.ReportTypeDevices),
+	}, resources.NewResourceHandler(slog.New(slog.NewTextHandler(io.Discard, nil))).HandleReportCatalog)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "report-data",
+		MIMEType:    "application/json",
+		URITemplate: tools.ReportDataURITemplate,
+	}, rt.ReadReportResource)
+
+	ctx := context.Background()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport); err != nil {
+		t.Fatalf("Server Connect() unexpected error: %v", err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, clientTransport)
+	if err != nil {
+		t.Fatalf("Client Connect() unexpected error: %v", err)
+	}
+	defer session.Close()
+
+	uri := "dap://reports/devices?limit=5&after=last%207%20days"
+	result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+	if err != nil {
+		t.Fatalf("ReadResource(%q) unexpected error: %v", uri, err)
+	}
+	if !strings.Contains(result.Contents[0].Text, `"device": "desktop"`) {
+		t.Errorf("Contents = %s, want device rows", result.Contents[0].Text)
+	}
+	if len(*requestedURLs) != 1 || !strings.Contains((*requestedURLs)[0], "limit=5") {
+		t.Errorf("Requested URLs = %v, want one request with limit=5", *requestedURLs)
+	}
+
+	uri = resources.ReportResourceURI(models.ReportTypeDevices)
+	result, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+	if err != nil {
+		t.Fatalf("ReadResource(%q) unexpected error: %v", uri, err)
+	}
+	if !strings.Contains(result.Contents[0].Text, `"dimensions"`) {
+		t.Errorf("Contents = %s, want the catalog entry of devices", result.Contents[0].Text)
+	}
+	if len(*requestedURLs) != 1 {
+		t.Errorf("Requested URLs = %v, want no request for the catalog entry", *requestedURLs)
+	}
+}
@@ -3,125 +3,359 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/rameshsunkara/go-mcp-example/config"
 	"github.com/rameshsunkara/go-mcp-example/models"
 )
 
+const (
+	// defaultLimit is the page size used when the caller does not provide one.
+	defaultLimit = 1000
+	// defaultMaxRecords is the record budget for fetch_all requests without max_records.
+	defaultMaxRecords = 10000
+	// maxRecordsLimit is the largest record budget a caller may request.
+	maxRecordsLimit = 100000
+)
+
 // ReportsTool handles analytics report fetching operations.
 type ReportsTool struct {
-	logger    *slog.Logger
-	config    *config.Config
-	apiClient *APIClient
+	logger      *slog.Logger
+	config      *config.Config
+	apiClient   *APIClient
+	cachePolicy CachePolicy
+	now         func() time.Time
 }
 
 // NewReportsTool creates a new ReportsTool with the provided logger, config, and API client.
 func NewReportsTool(logger *slog.Logger, cfg *config.Config, apiClient *APIClient) *ReportsTool {
+	return NewReportsToolWithClock(logger, cfg, apiClient, time.Now)
+}
+
+// NewReportsToolWithClock creates a new ReportsTool that resolves relative dates against
+// the provided clock (useful for testing).
+func NewReportsToolWithClock(logger *slog.Logger, cfg *config.Config, apiClient *APIClient,
+	now func() time.Time) *ReportsTool {
 	return &ReportsTool{
-		logger:    logger,
-		config:    cfg,
-		apiClient: apiClient,
+		logger:      logger,
+		config:      cfg,
+		apiClient:   apiClient,
+		cachePolicy: NewCachePolicy(cfg),
+		now:         now,
 	}
 }
 
 // GetReport implements the get_report tool.
+// Results carry the report both as human-readable text and as structured content.
 func (rt *ReportsTool) GetReport(ctx context.Context, _ *mcp.ServerSession,
-	params *mcp.CallToolParamsFor[models.ReportArgs]) (*mcp.CallToolResultFor[struct{}], error) {
+	params *mcp.CallToolParamsFor[models.ReportArgs]) (*mcp.CallToolResultFor[models.ReportResponse], error) {
 	args := params.Arguments
 
 	rt.logger.InfoContext(ctx, "Processing get_report tool call",
 		"report_name", args.ReportName,
+		"agency_name", args.AgencyName,
+		"domain", args.Domain,
+		"date_range", args.DateRange,
 		"limit", args.Limit)
 
-	// Validate report type
-	reportType := models.ReportType(args.ReportName)
-	if !reportType.IsValid() {
-		return nil, fmt.Errorf("invalid report type '%s'. Valid types: %v", args.ReportName, models.GetAllReportTypes())
-	}
-
-	// Build request parameters
-	reportParams := models.ReportParams{
-		Limit:  args.Limit,
-		Page:   args.Page,
-		After:  args.After,
-		Before: args.Before,
+	request, err := rt.prepareRequest(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
 	}
 
-	// Set defaults if not provided
-	if reportParams.Limit == 0 {
-		reportParams.Limit = 1000
-	}
-	if reportParams.Page == 0 {
-		reportParams.Page = 1
+	options, err := models.NewResultOptions(args.Filters, args.SortBy, args.SortOrder, args.Top)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
 	}
 
-	// Validate parameters
-	if err := reportParams.Validate(); err != nil {
+	if err = models.ValidateReportFields(args.Fields); err != nil {
 		return nil, fmt.Errorf("invalid parameters: %w", err)
 	}
 
-	// Build the API URL
-	apiURL, err := rt.buildReportsURL(args.ReportName, reportParams)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build API URL: %w", err)
+	if err = validateOutputFormat(args.OutputFormat); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
 	}
 
-	rt.logger.InfoContext(ctx, "Making API request", "url", apiURL)
-
-	// Make HTTP request
-	reports, fetchErr := rt.fetchReports(ctx, apiURL)
+	// Make HTTP request(s)
+	reports, pagination, fetchErr := rt.fetch(ctx, request, args.FetchAll, args.MaxRecords)
 	if fetchErr != nil {
 		// All errors are returned as MCP errors for consistent user experience
-		result := &mcp.CallToolResultFor[struct{}]{
-			Content: []mcp.Content{
-				&mcp.TextContent{Text: "Request failed: " + fetchErr.Error()},
-			},
-			IsError: true,
-		}
+		result := reportErrorResult("Request failed: " + fetchErr.Error())
 		return result, nil //nolint:nilerr // MCP tools return nil error when IsError is true
 	}
 
 	// Check if no data was returned
 	if len(reports) == 0 {
-		return &mcp.CallToolResultFor[struct{}]{
-			Content: []mcp.Content{
-				&mcp.TextContent{Text: "No data found for report: " + args.ReportName},
-			},
-			IsError: true,
-		}, nil
+		return reportErrorResult("No data found for report: " + args.ReportName), nil
+	}
+
+	// Filter, sort and trim the fetched rows
+	fetched := len(reports)
+	if !options.IsZero() {
+		reports = options.Apply(reports)
+	}
+
+	// Project rows onto the requested fields, for both the text and the structured content
+	if len(args.Fields) > 0 {
+		for i := range reports {
+			reports[i] = reports[i].Select(args.Fields)
+		}
 	}
 
 	// Format response
 	response := models.ReportResponse{
-		Data: reports,
+		Data:       reports,
+		Pagination: pagination,
 	}
 
-	responseJSON, err := json.MarshalIndent(response, "", "  ")
+	body, err := renderReport(response, args.Fields, args.OutputFormat, args.Compact)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response: %w", err)
+		return nil, fmt.Errorf("failed to render response: %w", err)
 	}
 
-	return &mcp.CallToolResultFor[struct{}]{
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Text: fmt.Sprintf("Analytics Report: %s\n\nFound %d records:\n\n%s",
-					args.ReportName, len(reports), string(responseJSON)),
+	summary := fmt.Sprintf("Found %d records", fetched)
+	if pagination != nil {
+		summary += fmt.Sprintf(" across %d pages", pagination.PagesRead)
+		if pagination.Truncated {
+			summary += " (truncated at max_records; more data is available)"
+		}
+	}
+	if !options.IsZero() {
+		summary += fmt.Sprintf(", showing %d after filtering and sorting", len(reports))
+	}
+
+	text := fmt.Sprintf("Analytics Report: %s\n\n%s:\n\n%s", args.ReportName, summary, body)
+	if warning := rt.quotaWarning(); warning != "" {
+		text += "\n\n" + warning
+	}
+
+	content := []mcp.Content{
+		&mcp.TextContent{Text: text},
+	}
+
+	// Attach the rows as a CSV document for clients that save or open resources
+	if args.EmbedCSV {
+		csvBody, csvErr := renderReport(response, args.Fields, OutputFormatCSV, false)
+		if csvErr != nil {
+			return nil, fmt.Errorf("failed to render CSV: %w", csvErr)
+		}
+		content = append(content, &mcp.EmbeddedResource{
+			Resource: &mcp.ResourceContents{
+				URI:      "dap://reports/" + url.PathEscape(args.ReportName) + "/data.csv",
+				MIMEType: "text/csv",
+				Text:     csvBody,
 			},
-		},
+		})
+	}
+
+	return &mcp.CallToolResultFor[models.ReportResponse]{
+		Content:           content,
+		StructuredContent: response,
 	}, nil
 }
 
+// prepareRequest builds the API request described by the tool arguments, resolving date
+// expressions and applying defaults, and validates it.
+func (rt *ReportsTool) prepareRequest(args models.ReportArgs) (models.ReportRequest, error) {
+	request := models.ReportRequest{
+		ReportName: args.ReportName,
+		AgencyName: args.AgencyName,
+		Domain:     args.Domain,
+		Parameters: models.ReportParams{
+			Limit:  args.Limit,
+			Page:   args.Page,
+			After:  args.After,
+			Before: args.Before,
+		},
+	}
+
+	// Resolve date expressions to concrete dates
+	if err := resolveDates(args, &request.Parameters, rt.now()); err != nil {
+		return models.ReportRequest{}, err
+	}
+
+	// Set defaults if not provided
+	if request.Parameters.Limit == 0 {
+		request.Parameters.Limit = defaultLimit
+	}
+	if request.Parameters.Page == 0 {
+		request.Parameters.Page = 1
+	}
+
+	if err := request.Validate(); err != nil {
+		return models.ReportRequest{}, err
+	}
+
+	if args.MaxRecords < 0 || args.MaxRecords > maxRecordsLimit {
+		return models.ReportRequest{}, fmt.Errorf("max_records must be between 0 and %d (0 uses the default), got %d",
+			maxRecordsLimit, args.MaxRecords)
+	}
+
+	return request, nil
+}
+
+// fetch fetches the requested page, or walks pages up to maxRecords when fetchAll is set or a
+// record budget is given. Pagination details are only returned for multi-page fetches.
+func (rt *ReportsTool) fetch(ctx context.Context, request models.ReportRequest, fetchAll bool,
+	maxRecords int) ([]models.Reports, *models.Pagination, error) {
+	if !fetchAll && maxRecords == 0 {
+		reports, err := rt.fetchReportPage(ctx, request)
+		return reports, nil, err
+	}
+
+	if maxRecords == 0 {
+		maxRecords = defaultMaxRecords
+	}
+	return rt.fetchAllReports(ctx, request, maxRecords)
+}
+
+// reportErrorResult creates an error tool result whose structured content carries the message.
+func reportErrorResult(message string) *mcp.CallToolResultFor[models.ReportResponse] {
+	return &mcp.CallToolResultFor[models.ReportResponse]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: message},
+		},
+		StructuredContent: models.ReportResponse{
+			Data:  []models.Reports{},
+			Error: &models.Error{Message: message},
+		},
+		IsError: true,
+	}
+}
+
+// quotaWarning returns a note for the model when the API quota is about to run out.
+func (rt *ReportsTool) quotaWarning() string {
+	quota := rt.apiClient.Quota()
+	if !quota.Low() {
+		return ""
+	}
+
+	remaining := strconv.Itoa(quota.Remaining)
+	if quota.Limit > 0 {
+		remaining += " of " + strconv.Itoa(quota.Limit)
+	}
+	return "WARNING: Only " + remaining + " API requests remain in the current hourly quota. " +
+		"Prefer narrower queries and avoid repeating requests."
+}
+
+// fetchReportPage fetches the single page of report data described by the request.
+func (rt *ReportsTool) fetchReportPage(ctx context.Context, request models.ReportRequest) ([]models.Reports, error) {
+	apiURL, err := rt.buildReportsURL(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build API URL: %w", err)
+	}
+
+	return rt.fetchReports(ctx, apiURL, rt.cachePolicy.TTL(request, rt.now()))
+}
+
+// refreshReportPage fetches the single page of report data described by the request from the API,
+// bypassing the response cache, and caches the fresh response for later reads.
+func (rt *ReportsTool) refreshReportPage(ctx context.Context, request models.ReportRequest) ([]models.Reports, error) {
+	apiURL, err := rt.buildReportsURL(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build API URL: %w", err)
+	}
+
+	return rt.refreshReports(ctx, apiURL, rt.cachePolicy.TTL(request, rt.now()))
+}
+
+// resolveDates fills params.After and params.Before from the date arguments, resolving
+// expressions like "last 7 days" against now. date_range sets both ends at once.
+func resolveDates(args models.ReportArgs, params *models.ReportParams, now time.Time) error {
+	if args.DateRange == "" {
+		return params.ResolveDates(now)
+	}
+
+	if args.After != "" || args.Before != "" {
+		return errors.New("date_range cannot be combined with after or before")
+	}
+
+	dates, err := models.ParseDateExpression(args.DateRange, now)
+	if err != nil {
+		return fmt.Errorf("invalid date_range: %w", err)
+	}
+	params.After = dates.After()
+	params.Before = dates.Before()
+	return nil
+}
+
+// fetchAllReports walks report pages starting at the requested page until the API
+// returns a short page or maxRecords have been collected.
+func (rt *ReportsTool) fetchAllReports(ctx context.Context, request models.ReportRequest,
+	maxRecords int) ([]models.Reports, *models.Pagination, error) {
+	pagination := &models.Pagination{}
+	var reports []models.Reports
+
+	for {
+		page, err := rt.fetchReportPage(ctx, request)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch page %d: %w", request.Parameters.Page, err)
+		}
+		pagination.PagesRead++
+		reports = append(reports, page...)
+
+		if len(reports) >= maxRecords {
+			pagination.Truncated = len(reports) > maxRecords
+			if !pagination.Truncated && len(page) == request.Parameters.Limit {
+				// A full last page ending on the budget may or may not be followed by more records
+				pagination.Truncated = rt.hasRecordAfter(ctx, request)
+			}
+			reports = reports[:maxRecords]
+			break
+		}
+		if len(page) < request.Parameters.Limit {
+			break
+		}
+		request.Parameters.Page++
+	}
+
+	pagination.Records = len(reports)
+	rt.apiClient.Metrics.ObservePagination(pagination.PagesRead)
+	rt.logger.InfoContext(ctx, "Finished multi-page fetch",
+		"pages_read", pagination.PagesRead,
+		"records", pagination.Records,
+		"truncated", pagination.Truncated)
+
+	return reports, pagination, nil
+}
+
+// hasRecordAfter reports whether the API holds a record after the page of the request, by fetching
+// that single record. If the check fails, more records are assumed to be available.
+func (rt *ReportsTool) hasRecordAfter(ctx context.Context, request models.ReportRequest) bool {
+	probe := request
+	probe.Parameters.Limit = 1
+	probe.Parameters.Page = request.Parameters.Page*request.Parameters.Limit + 1
+
+	next, err := rt.fetchReportPage(ctx, probe)
+	if err != nil {
+		rt.logger.WarnContext(ctx, "Failed to check for more records", "page", probe.Parameters.Page, "error", err)
+		return true
+	}
+	return len(next) > 0
+}
+
 // buildReportsURL builds a complete URL for fetching report data.
-func (rt *ReportsTool) buildReportsURL(reportName string, params models.ReportParams) (string, error) {
+// Requests scoped to an agency or a domain are routed to their dedicated endpoints.
+func (rt *ReportsTool) buildReportsURL(request models.ReportRequest) (string, error) {
 	// Build base URL
-	baseURL := rt.apiClient.BaseURL + "/reports/" + reportName + "/data"
+	reportPath := "/reports/" + url.PathEscape(request.ReportName) + "/data"
+	var baseURL string
+	switch {
+	case request.AgencyName != "":
+		baseURL = rt.apiClient.BaseURL + "/agencies/" + url.PathEscape(request.AgencyName) + reportPath
+	case request.Domain != "":
+		baseURL = rt.apiClient.BaseURL + "/domain/" + url.PathEscape(request.Domain) + reportPath
+	default:
+		baseURL = rt.apiClient.BaseURL + reportPath
+	}
 
 	// Parse URL to add query parameters
 	u, err := url.Parse(baseURL)
@@ -131,6 +365,7 @@ func (rt *ReportsTool) buildReportsURL(reportName string, params models.ReportPa
 
 	// Add query parameters
 	q := u.Query()
+	params := request.Parameters
 
 	if params.Limit > 0 {
 		q.Set("limit", strconv.Itoa(params.Limit))
@@ -152,8 +387,55 @@ func (rt *ReportsTool) buildReportsURL(reportName string, params models.ReportPa
 	return u.String(), nil
 }
 
-// fetchReports makes the HTTP request to fetch analytics data.
-func (rt *ReportsTool) fetchReports(ctx context.Context, apiURL string) ([]models.Reports, error) {
+// fetchReports fetches analytics data, serving it from the response cache when possible.
+// Successful responses are cached for ttl.
+func (rt *ReportsTool) fetchReports(ctx context.Context, apiURL string, ttl time.Duration) ([]models.Reports, error) {
+	cache := rt.apiClient.Cache
+	cacheKey := CacheKey(apiURL)
+
+	if cache != nil {
+		body, ok := cache.Get(cacheKey)
+		rt.apiClient.Metrics.ObserveCacheLookup(ok)
+		if ok {
+			reports, err := parseReports(body)
+			if err == nil {
+				rt.logger.InfoContext(ctx, "Cache hit", "url", apiURL, "count", len(reports))
+				return reports, nil
+			}
+			rt.logger.WarnContext(ctx, "Ignoring unreadable cache entry", "url", apiURL, "error", err)
+		} else {
+			rt.logger.DebugContext(ctx, "Cache miss", "url", apiURL)
+		}
+	}
+
+	return rt.refreshReports(ctx, apiURL, ttl)
+}
+
+// refreshReports fetches analytics data from the API without consulting the response cache.
+// Successful responses are cached for ttl.
+func (rt *ReportsTool) refreshReports(ctx context.Context, apiURL string, ttl time.Duration) ([]models.Reports, error) {
+	rt.logger.InfoContext(ctx, "Making API request", "url", apiURL)
+
+	body, err := rt.doReportsRequest(ctx, apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := parseReports(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if cache := rt.apiClient.Cache; cache != nil {
+		cache.Set(CacheKey(apiURL), body, ttl)
+	}
+
+	rt.logger.InfoContext(ctx, "Successfully fetched reports", "count", len(reports))
+	return reports, nil
+}
+
+// doReportsRequest makes the HTTP request and returns the body of a successful response.
+func (rt *ReportsTool) doReportsRequest(ctx context.Context, apiURL string) ([]byte, error) {
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
@@ -183,12 +465,14 @@ func (rt *ReportsTool) fetchReports(ctx context.Context, apiURL string) ([]model
 		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
-	// Parse JSON response
+	return body, nil
+}
+
+// parseReports parses a JSON API response body into report rows.
+func parseReports(body []byte) ([]models.Reports, error) {
 	var reports []models.Reports
-	if parseErr := json.Unmarshal(body, &reports); parseErr != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %w", parseErr)
+	if err := json.Unmarshal(body, &reports); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
 	}
-
-	rt.logger.InfoContext(ctx, "Successfully fetched reports", "count", len(reports))
 	return reports, nil
 }
@@ -0,0 +1,687 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/config"
+	"github.com/rameshsunkara/go-mcp-example/models"
+	"github.com/rameshsunkara/go-mcp-example/tools"
+)
+
+const sampleReportsJSON = `[
+	{"id": 1, "report_name": "devices", "report_agency": "gsa", "date": "2024-01-01", "device": "desktop", "visits": 100},
+	{"id": 2, "report_name": "devices", "report_agency": "gsa", "date": "2024-01-01", "device": "mobile", "visits": 50}
+]`
+
+// newTestReportsTool creates a ReportsTool backed by a mock HTTP client that records request URLs.
+func newTestReportsTool(t *testing.T, body string, statusCode int) (*tools.ReportsTool, *[]string) {
+	t.Helper()
+
+	var requestedURLs []string
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			requestedURLs = append(requestedURLs, req.URL.String())
+			return &http.Response{
+				StatusCode: statusCode,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	apiClient := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+	return tools.NewReportsTool(logger, &config.Config{}, apiClient), &requestedURLs
+}
+
+// callGetReport invokes the get_report tool handler with the given arguments.
+func callGetReport(rt *tools.ReportsTool,
+	args models.ReportArgs) (*mcp.CallToolResultFor[models.ReportResponse], error) {
+	return rt.GetReport(context.Background(), nil, &mcp.CallToolParamsFor[models.ReportArgs]{
+		Name:      "get_report",
+		Arguments: args,
+	})
+}
+
+func TestReportsTool_GetReport_Routing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		args        models.ReportArgs
+		expectedURL string
+	}{
+		{
+			name:        "government-wide report",
+			args:        models.ReportArgs{ReportName: "devices"},
+			expectedURL: "https://api.example.com/reports/devices/data?limit=1000&page=1",
+		},
+		{
+			name: "agency report",
+			args: models.ReportArgs{ReportName: "devices", AgencyName: "general-services-administration"},
+			expectedURL: "https://api.example.com/agencies/general-services-administration" +
+				"/reports/devices/data?limit=1000&page=1",
+		},
+		{
+			name:        "domain report",
+			args:        models.ReportArgs{ReportName: "top-pages", Domain: "usa.gov", Limit: 10},
+			expectedURL: "https://api.example.com/domain/usa.gov/reports/top-pages/data?limit=10&page=1",
+		},
+		{
+			name: "date filters",
+			args: models.ReportArgs{ReportName: "traffic", After: "2024-01-01", Before: "2024-01-31"},
+			expectedURL: "https://api.example.com/reports/traffic/data?" +
+				"after=2024-01-01&before=2024-01-31&limit=1000&page=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt, requestedURLs := newTestReportsTool(t, sampleReportsJSON, http.StatusOK)
+
+			result, err := callGetReport(rt, tt.args)
+			if err != nil {
+				t.Fatalf("GetReport() unexpected error: %v", err)
+			}
+			if result.IsError {
+				t.Fatalf("GetReport() returned error result: %+v", result.Content)
+			}
+
+			if len(*requestedURLs) != 1 {
+				t.Fatalf("Expected 1 request, got %d", len(*requestedURLs))
+			}
+			if (*requestedURLs)[0] != tt.expectedURL {
+				t.Errorf("Request URL = %s, want %s", (*requestedURLs)[0], tt.expectedURL)
+			}
+		})
+	}
+}
+
+func TestReportsTool_GetReport_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   models.ReportArgs
+		errMsg string
+	}{
+		{
+			name:   "invalid report type",
+			args:   models.ReportArgs{ReportName: "unknown"},
+			errMsg: "invalid report type",
+		},
+		{
+			name:   "agency and domain together",
+			args:   models.ReportArgs{ReportName: "devices", AgencyName: "gsa", Domain: "gsa.gov"},
+			errMsg: "agency_name and domain cannot be used together",
+		},
+		{
+			name:   "malformed date",
+			args:   models.ReportArgs{ReportName: "traffic", After: "2024/01/01"},
+			errMsg: "unrecognized date expression",
+		},
+		{
+			name:   "after later than before",
+			args:   models.ReportArgs{ReportName: "traffic", After: "2024-02-01", Before: "2024-01-01"},
+			errMsg: "must not be later than before date",
+		},
+		{
+			name:   "date_range with after",
+			args:   models.ReportArgs{ReportName: "traffic", DateRange: "2024-Q1", After: "2024-01-01"},
+			errMsg: "date_range cannot be combined with after or before",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt, requestedURLs := newTestReportsTool(t, sampleReportsJSON, http.StatusOK)
+
+			_, err := callGetReport(rt, tt.args)
+			if err == nil {
+				t.Fatal("GetReport() expected error but got none")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("GetReport() error = %v, want error containing %q", err, tt.errMsg)
+			}
+			if len(*requestedURLs) != 0 {
+				t.Errorf("Expected no requests, got %d", len(*requestedURLs))
+			}
+		})
+	}
+}
+
+func TestReportsTool_GetReport_DateExpressions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		args          models.ReportArgs
+		expectedQuery string
+	}{
+		{
+			name:          "date_range relative to clock",
+			args:          models.ReportArgs{ReportName: "traffic", DateRange: "last 7 days"},
+			expectedQuery: "after=2024-03-08&before=2024-03-14&limit=1000&page=1",
+		},
+		{
+			name:          "date_range quarter",
+			args:          models.ReportArgs{ReportName: "traffic", DateRange: "2024-Q1"},
+			expectedQuery: "after=2024-01-01&before=2024-03-31&limit=1000&page=1",
+		},
+		{
+			name:          "expressions in after and before",
+			args:          models.ReportArgs{ReportName: "traffic", After: "2023-12", Before: "yesterday"},
+			expectedQuery: "after=2023-12-01&before=2024-03-14&limit=1000&page=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requestedURLs []string
+			mockClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					requestedURLs = append(requestedURLs, req.URL.String())
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(sampleReportsJSON)),
+					}, nil
+				},
+			}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			apiClient := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+			clock := func() time.Time { return time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC) }
+			rt := tools.NewReportsToolWithClock(logger, &config.Config{}, apiClient, clock)
+
+			if _, err := callGetReport(rt, tt.args); err != nil {
+				t.Fatalf("GetReport() unexpected error: %v", err)
+			}
+
+			expectedURL := "https://api.example.com/reports/traffic/data?" + tt.expectedQuery
+			if len(requestedURLs) != 1 || requestedURLs[0] != expectedURL {
+				t.Errorf("Requested URLs = %v, want [%s]", requestedURLs, expectedURL)
+			}
+		})
+	}
+}
+
+// newPagedReportsTool creates a ReportsTool whose mock API serves totalRecords records
+// split into pages according to the limit and page query parameters.
+func newPagedReportsTool(t *testing.T, totalRecords int) (*tools.ReportsTool, *[]string) {
+	t.Helper()
+
+	var requestedURLs []string
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			requestedURLs = append(requestedURLs, req.URL.String())
+
+			limit, _ := strconv.Atoi(req.URL.Query().Get("limit"))
+			page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+			start := min((page-1)*limit, totalRecords)
+			end := min(start+limit, totalRecords)
+
+			rows := make([]models.Reports, 0, end-start)
+			for i := start; i < end; i++ {
+				rows = append(rows, models.Reports{ID: i + 1, ReportName: "traffic", Date: "2024-01-01", Visits: i})
+			}
+			body, err := json.Marshal(rows)
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(string(body))),
+			}, nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	apiClient := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+	return tools.NewReportsTool(logger, &config.Config{}, apiClient), &requestedURLs
+}
+
+// decodeReportResponse returns the structured report response of a get_report result.
+func decodeReportResponse(t *testing.T, result *mcp.CallToolResultFor[models.ReportResponse]) models.ReportResponse {
+	t.Helper()
+
+	if result.IsError {
+		t.Fatalf("GetReport() returned error result: %+v", result.Content)
+	}
+	return result.StructuredContent
+}
+
+func TestReportsTool_GetReport_FetchAll(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		totalRecords      int
+		args              models.ReportArgs
+		expectedRequests  int
+		expectedPages     int
+		expectedRecords   int
+		expectedTruncated bool
+	}{
+		{
+			name:             "stops at short page",
+			totalRecords:     25,
+			args:             models.ReportArgs{ReportName: "traffic", Limit: 10, FetchAll: true},
+			expectedRequests: 3,
+			expectedRecords:  25,
+		},
+		{
+			name:             "stops at empty page when data divides evenly",
+			totalRecords:     20,
+			args:             models.ReportArgs{ReportName: "traffic", Limit: 10, FetchAll: true},
+			expectedRequests: 3,
+			expectedRecords:  20,
+		},
+		{
+			name:              "stops at record budget",
+			totalRecords:      100,
+			args:              models.ReportArgs{ReportName: "traffic", Limit: 10, MaxRecords: 25},
+			expectedRequests:  3,
+			expectedRecords:   25,
+			expectedTruncated: true,
+		},
+		{
+			name:              "budget reached on full page",
+			totalRecords:      100,
+			args:              models.ReportArgs{ReportName: "traffic", Limit: 10, MaxRecords: 20},
+			expectedRequests:  3,
+			expectedPages:     2,
+			expectedRecords:   20,
+			expectedTruncated: true,
+		},
+		{
+			name:             "budget reached on the last record",
+			totalRecords:     20,
+			args:             models.ReportArgs{ReportName: "traffic", Limit: 10, MaxRecords: 20},
+			expectedRequests: 3,
+			expectedPages:    2,
+			expectedRecords:  20,
+		},
+		{
+			name:             "starts at requested page",
+			totalRecords:     25,
+			args:             models.ReportArgs{ReportName: "traffic", Limit: 10, Page: 2, FetchAll: true},
+			expectedRequests: 2,
+			expectedRecords:  15,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt, requestedURLs := newPagedReportsTool(t, tt.totalRecords)
+
+			result, err := callGetReport(rt, tt.args)
+			if err != nil {
+				t.Fatalf("GetReport() unexpected error: %v", err)
+			}
+
+			if len(*requestedURLs) != tt.expectedRequests {
+				t.Errorf("Requests = %d, want %d", len(*requestedURLs), tt.expectedRequests)
+			}
+
+			response := decodeReportResponse(t, result)
+			if len(response.Data) != tt.expectedRecords {
+				t.Errorf("Records = %d, want %d", len(response.Data), tt.expectedRecords)
+			}
+			if response.Pagination == nil {
+				t.Fatal("Expected pagination metadata")
+			}
+			// Checking for a record after the budget is not a page read
+			expectedPages := tt.expectedPages
+			if expectedPages == 0 {
+				expectedPages = tt.expectedRequests
+			}
+			if response.Pagination.PagesRead != expectedPages {
+				t.Errorf("PagesRead = %d, want %d", response.Pagination.PagesRead, expectedPages)
+			}
+			if response.Pagination.Truncated != tt.expectedTruncated {
+				t.Errorf("Truncated = %v, want %v", response.Pagination.Truncated, tt.expectedTruncated)
+			}
+		})
+	}
+}
+
+func TestReportsTool_GetReport_SinglePageHasNoPagination(t *testing.T) {
+	t.Parallel()
+
+	rt, requestedURLs := newPagedReportsTool(t, 25)
+
+	result, err := callGetReport(rt, models.ReportArgs{ReportName: "traffic", Limit: 10})
+	if err != nil {
+		t.Fatalf("GetReport() unexpected error: %v", err)
+	}
+
+	if len(*requestedURLs) != 1 {
+		t.Errorf("Requests = %d, want 1", len(*requestedURLs))
+	}
+	if response := decodeReportResponse(t, result); response.Pagination != nil {
+		t.Errorf("Pagination = %+v, want nil", response.Pagination)
+	}
+}
+
+func TestReportsTool_GetReport_InvalidMaxRecords(t *testing.T) {
+	t.Parallel()
+
+	for _, maxRecords := range []int{-1, 100001} {
+		t.Run(fmt.Sprintf("max_records=%d", maxRecords), func(t *testing.T) {
+			t.Parallel()
+
+			rt, _ := newPagedReportsTool(t, 10)
+
+			_, err := callGetReport(rt, models.ReportArgs{ReportName: "traffic", MaxRecords: maxRecords})
+			if err == nil || !strings.Contains(err.Error(), "max_records must be between 0 and 100000 (0 uses the default)") {
+				t.Errorf("GetReport() error = %v, want max_records validation error", err)
+			}
+		})
+	}
+}
+
+func TestReportsTool_GetReport_UsesCache(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	mockClient := &MockHTTPClient{
+		DoFunc: func(_ *http.Request) (*http.Response, error) {
+			requests++
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(sampleReportsJSON)),
+			}, nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	apiClient := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+	apiClient.Cache = tools.NewMemoryCache(10)
+	rt := tools.NewReportsTool(logger, &config.Config{CacheTTL: time.Minute}, apiClient)
+
+	for range 2 {
+		result, err := callGetReport(rt, models.ReportArgs{ReportName: "devices"})
+		if err != nil {
+			t.Fatalf("GetReport() unexpected error: %v", err)
+		}
+		if len(decodeReportResponse(t, result).Data) != 2 {
+			t.Error("Expected cached response to contain 2 records")
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Requests = %d, want 1 (second call should be served from cache)", requests)
+	}
+
+	// A different page is a different cache entry
+	if _, err := callGetReport(rt, models.ReportArgs{ReportName: "devices", Page: 2}); err != nil {
+		t.Fatalf("GetReport() unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Requests = %d, want 2", requests)
+	}
+}
+
+func TestReportsTool_GetReport_DoesNotCacheErrors(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	mockClient := &MockHTTPClient{
+		DoFunc: func(_ *http.Request) (*http.Response, error) {
+			requests++
+			return &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Body:       io.NopCloser(strings.NewReader(`{"error":"boom"}`)),
+			}, nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	apiClient := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+	apiClient.Cache = tools.NewMemoryCache(10)
+	rt := tools.NewReportsTool(logger, &config.Config{CacheTTL: time.Minute}, apiClient)
+
+	for range 2 {
+		result, err := callGetReport(rt, models.ReportArgs{ReportName: "devices"})
+		if err != nil {
+			t.Fatalf("GetReport() unexpected error: %v", err)
+		}
+		if !result.IsError {
+			t.Error("Expected error result for failed API request")
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("Requests = %d, want 2 (errors must not be cached)", requests)
+	}
+}
+
+func TestReportsTool_GetReport_StructuredContent(t *testing.T) {
+	t.Parallel()
+
+	rt, _ := newTestReportsTool(t, sampleReportsJSON, http.StatusOK)
+
+	result, err := callGetReport(rt, models.ReportArgs{ReportName: "devices"})
+	if err != nil {
+		t.Fatalf("GetReport() unexpected error: %v", err)
+	}
+
+	response := decodeReportResponse(t, result)
+	if len(response.Data) != 2 || response.Data[0].Device != "desktop" {
+		t.Fatalf("StructuredContent = %+v, want the 2 device rows", response)
+	}
+
+	// The text content carries the same data for clients without structured output support
+	text, ok := result.Content[0].(*mcp.TextContent)
+	if !ok {
+		t.Fatalf("Expected TextContent, got %T", result.Content[0])
+	}
+	var fromText models.ReportResponse
+	if unmarshalErr := json.Unmarshal([]byte(text.Text[strings.Index(text.Text, "{"):]), &fromText); unmarshalErr != nil {
+		t.Fatalf("Failed to unmarshal text content: %v", unmarshalErr)
+	}
+	if !reflect.DeepEqual(fromText, response) {
+		t.Errorf("Text content = %+v, want %+v", fromText, response)
+	}
+}
+
+func TestReportsTool_GetReport_StructuredError(t *testing.T) {
+	t.Parallel()
+
+	rt, _ := newTestReportsTool(t, `{"error":"unavailable"}`, http.StatusInternalServerError)
+
+	result, err := callGetReport(rt, models.ReportArgs{ReportName: "devices"})
+	if err != nil {
+		t.Fatalf("GetReport() unexpected error: %v", err)
+	}
+
+	if !result.IsError {
+		t.Fatal("Expected error result")
+	}
+	if result.StructuredContent.Error == nil ||
+		!strings.Contains(result.StructuredContent.Error.Message, "status 500") {
+		t.Errorf("StructuredContent.Error = %+v, want the request failure", result.StructuredContent.Error)
+	}
+}
+
+func TestReportsTool_GetReport_OutputSchema(t *testing.T) {
+	t.Parallel()
+
+	rt, _ := newTestReportsTool(t, sampleReportsJSON, http.StatusOK)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+
+	tool := &mcp.Tool{Name: "get_report", Description: tools.GetReportToolDescription}
+	mcp.AddTool(server, tool, rt.GetReport)
+
+	if tool.OutputSchema == nil {
+		t.Fatal("get_report should declare an output schema")
+	}
+	if tool.OutputSchema.Type != "object" {
+		t.Errorf("OutputSchema.Type = %q, want object", tool.OutputSchema.Type)
+	}
+	for _, property := range []string{"data", "pagination", "error"} {
+		if _, ok := tool.OutputSchema.Properties[property]; !ok {
+			t.Errorf("OutputSchema is missing property %q", property)
+		}
+	}
+}
+
+func TestReportsTool_GetReport_FilterSortTop(t *testing.T) {
+	t.Parallel()
+
+	body := `[
+		{"id": 1, "report_name": "countries", "date": "2024-01-01", "country": "Canada", "visits": 300},
+		{"id": 2, "report_name": "countries", "date": "2024-01-01", "country": "United States", "visits": 5000},
+		{"id": 3, "report_name": "countries", "date": "2024-01-01", "country": "Mexico", "visits": 800},
+		{"id": 4, "report_name": "countries", "date": "2024-01-01", "country": "Germany", "visits": 1200}
+	]`
+	rt, requestedURLs := newTestReportsTool(t, body, http.StatusOK)
+
+	result, err := callGetReport(rt, models.ReportArgs{
+		ReportName: "countries",
+		Filters:    []string{"visits > 500", "country != Mexico"},
+		SortBy:     "visits",
+		Top:        1,
+	})
+	if err != nil {
+		t.Fatalf("GetReport() unexpected error: %v", err)
+	}
+
+	// Filtering happens after fetching, so the API request is unchanged
+	expectedURL := "https://api.example.com/reports/countries/data?limit=1000&page=1"
+	if len(*requestedURLs) != 1 || (*requestedURLs)[0] != expectedURL {
+		t.Errorf("Requested URLs = %v, want [%s]", *requestedURLs, expectedURL)
+	}
+
+	response := decodeReportResponse(t, result)
+	if len(response.Data) != 1 || response.Data[0].Country != "United States" {
+		t.Errorf("Data = %+v, want only United States", response.Data)
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "Found 4 records, showing 1 after filtering and sorting") {
+		t.Errorf("Result text missing filter summary:\n%s", text)
+	}
+}
+
+func TestReportsTool_GetReport_InvalidResultOptions(t *testing.T) {
+	t.Parallel()
+
+	rt, requestedURLs := newTestReportsTool(t, sampleReportsJSON, http.StatusOK)
+
+	_, err := callGetReport(rt, models.ReportArgs{ReportName: "devices", Filters: []string{"visits >> 1"}})
+	if err == nil || !strings.Contains(err.Error(), "invalid filter") {
+		t.Errorf("GetReport() error = %v, want invalid filter error", err)
+	}
+	if len(*requestedURLs) != 0 {
+		t.Errorf("Expected no requests, got %d", len(*requestedURLs))
+	}
+}
+
+func TestReportsTool_GetReport_FieldsProjection(t *testing.T) {
+	t.Parallel()
+
+	body := `[
+		{"id": 1, "report_name": "devices", "report_agency": "gsa", "date": "2024-01-01",
+			"device": "desktop", "browser": "Safari", "visits": 100},
+		{"id": 2, "report_name": "devices", "report_agency": "gsa", "date": "2024-01-01",
+			"device": "mobile", "browser": "Chrome", "visits": 50}
+	]`
+	tests := []struct {
+		name     string
+		compact  bool
+		expected string
+	}{
+		{
+			name:     "indented",
+			compact:  false,
+			expected: "{\n  \"data\": [\n    {\n      \"id\": 1,\n      \"report_name\": \"devices\",",
+		},
+		{
+			name:    "compact",
+			compact: true,
+			expected: `{"data":[{"id":1,"report_name":"devices","report_agency":"gsa","date":"2024-01-01",` +
+				`"device":"desktop","visits":100},`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt, _ := newTestReportsTool(t, body, http.StatusOK)
+
+			result, err := callGetReport(rt, models.ReportArgs{
+				ReportName: "devices",
+				Fields:     []string{"device", "visits"},
+				Compact:    tt.compact,
+			})
+			if err != nil {
+				t.Fatalf("GetReport() unexpected error: %v", err)
+			}
+
+			text := result.Content[0].(*mcp.TextContent).Text
+			if !strings.Contains(text, tt.expected) {
+				t.Errorf("Result text missing %q:\n%s", tt.expected, text)
+			}
+			if strings.Contains(text, "browser") {
+				t.Errorf("Result text should not contain unrequested fields:\n%s", text)
+			}
+
+			// The text rows are the structured rows
+			response := decodeReportResponse(t, result)
+			structured, err := json.Marshal(response)
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %v", err)
+			}
+			if tt.compact && !strings.Contains(text, string(structured)) {
+				t.Errorf("Result text does not match the structured content %s:\n%s", structured, text)
+			}
+			if response.Data[0].Device != "desktop" || response.Data[0].Browser != "" {
+				t.Errorf("Structured row = %+v, want device without browser", response.Data[0])
+			}
+		})
+	}
+}
+
+func TestReportsTool_GetReport_InvalidFields(t *testing.T) {
+	t.Parallel()
+
+	rt, requestedURLs := newTestReportsTool(t, sampleReportsJSON, http.StatusOK)
+
+	_, err := callGetReport(rt, models.ReportArgs{ReportName: "devices", Fields: []string{"device", "hits"}})
+	if err == nil || !strings.Contains(err.Error(), "invalid field 'hits'") {
+		t.Errorf("GetReport() error = %v, want invalid field error", err)
+	}
+	if len(*requestedURLs) != 0 {
+		t.Errorf("Expected no requests, got %d", len(*requestedURLs))
+	}
+}
+
+func TestGetReportToolDescription_ListsReportCatalog(t *testing.T) {
+	t.Parallel()
+
+	for _, report := range models.ReportCatalog() {
+		line := fmt.Sprintf("- %q: %s\n", report.Name, report.Description)
+		if !strings.Contains(tools.GetReportToolDescription, line) {
+			t.Errorf("GetReportToolDescription is missing report line %q", line)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/config"
+)
+
+// RetryPolicy controls how APIClient retries failed requests.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first one; values <= 1 disable retries
+	BaseDelay   time.Duration // Delay before the first retry, doubled on every further attempt
+	MaxDelay    time.Duration // Upper bound for a single delay, including server-provided Retry-After
+	Jitter      float64       // Random spread applied to each delay, as a fraction between 0 and 1
+}
+
+// NewRetryPolicy creates a RetryPolicy from the configured retry settings.
+func NewRetryPolicy(cfg *config.Config) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: cfg.RetryMaxAttempts,
+		BaseDelay:   cfg.RetryBaseDelay,
+		MaxDelay:    cfg.RetryMaxDelay,
+		Jitter:      cfg.RetryJitter,
+	}
+}
+
+// enabled reports whether the policy allows any retries.
+func (p RetryPolicy) enabled() bool {
+	return p.MaxAttempts > 1
+}
+
+// backoff returns the delay before the given retry attempt (1 for the first retry).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(retry-1))
+	if p.Jitter > 0 {
+		// Spread the delay evenly within +/- Jitter so concurrent clients do not retry in lockstep
+		delay *= 1 + p.Jitter*(2*rand.Float64()-1) //nolint:gosec // jitter does not need a secure source
+	}
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+// isIdempotent reports whether requests with the given method are safe to retry.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryableStatus reports whether a response status indicates a transient failure.
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
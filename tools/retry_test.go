@@ -0,0 +1,267 @@
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rameshsunkara/go-mcp-example/config"
+	"github.com/rameshsunkara/go-mcp-example/tools"
+)
+
+// scriptedResponse is a single response or error returned by scriptedHTTPClient.
+type scriptedResponse struct {
+	status     int
+	retryAfter string
+	err        error
+}
+
+// newScriptedClient creates an APIClient whose HTTP client replays the given responses in order,
+// repeating the last one once the script is exhausted. It returns a pointer to the attempt count.
+func newScriptedClient(policy tools.RetryPolicy, script ...scriptedResponse) (*tools.APIClient, *int) {
+	var attempts int
+	mockClient := &MockHTTPClient{
+		DoFunc: func(_ *http.Request) (*http.Response, error) {
+			step := script[min(attempts, len(script)-1)]
+			attempts++
+			if step.err != nil {
+				return nil, step.err
+			}
+			resp := &http.Response{
+				StatusCode: step.status,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(`{}`)),
+			}
+			if step.retryAfter != "" {
+				resp.Header.Set("Retry-After", step.retryAfter)
+			}
+			return resp, nil
+		},
+	}
+
+	client := tools.NewAPIClientWithHTTPClient("https://api.example.com", "test-key", mockClient)
+	client.Retry = policy
+	return client, &attempts
+}
+
+// fastRetryPolicy is a retry policy with delays short enough for unit tests.
+func fastRetryPolicy() tools.RetryPolicy {
+	return tools.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    50 * time.Millisecond,
+		Jitter:      0.5,
+	}
+}
+
+func TestAPIClient_DoRequest_Retry(t *testing.T) {
+	t.Parallel()
+
+	networkErr := &mockError{message: "connection reset"}
+
+	tests := []struct {
+		name             string
+		method           string
+		policy           tools.RetryPolicy
+		script           []scriptedResponse
+		expectedAttempts int
+		expectedStatus   int
+		expectError      bool
+	}{
+		{
+			name:             "retries service unavailable then succeeds",
+			method:           http.MethodGet,
+			policy:           fastRetryPolicy(),
+			script:           []scriptedResponse{{status: 503}, {status: 200}},
+			expectedAttempts: 2,
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "honors Retry-After in seconds",
+			method:           http.MethodGet,
+			policy:           fastRetryPolicy(),
+			script:           []scriptedResponse{{status: 429, retryAfter: "0"}, {status: 200}},
+			expectedAttempts: 2,
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:   "honors Retry-After as HTTP date",
+			method: http.MethodGet,
+			policy: fastRetryPolicy(),
+			script: []scriptedResponse{
+				{status: 429, retryAfter: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)},
+				{status: 200},
+			},
+			expectedAttempts: 2,
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "gives up when Retry-After exceeds max delay",
+			method:           http.MethodGet,
+			policy:           fastRetryPolicy(),
+			script:           []scriptedResponse{{status: 429, retryAfter: "3600"}, {status: 200}},
+			expectedAttempts: 1,
+			expectedStatus:   http.StatusTooManyRequests,
+		},
+		{
+			name:             "retries transport errors",
+			method:           http.MethodGet,
+			policy:           fastRetryPolicy(),
+			script:           []scriptedResponse{{err: networkErr}, {status: 200}},
+			expectedAttempts: 2,
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "returns last response when attempts are exhausted",
+			method:           http.MethodGet,
+			policy:           fastRetryPolicy(),
+			script:           []scriptedResponse{{status: 502}},
+			expectedAttempts: 3,
+			expectedStatus:   http.StatusBadGateway,
+		},
+		{
+			name:             "returns last error when attempts are exhausted",
+			method:           http.MethodGet,
+			policy:           fastRetryPolicy(),
+			script:           []scriptedResponse{{err: networkErr}},
+			expectedAttempts: 3,
+			expectError:      true,
+		},
+		{
+			name:             "does not retry non-retryable status",
+			method:           http.MethodGet,
+			policy:           fastRetryPolicy(),
+			script:           []scriptedResponse{{status: 404}, {status: 200}},
+			expectedAttempts: 1,
+			expectedStatus:   http.StatusNotFound,
+		},
+		{
+			name:             "does not retry non-idempotent methods",
+			method:           http.MethodPost,
+			policy:           fastRetryPolicy(),
+			script:           []scriptedResponse{{status: 503}, {status: 200}},
+			expectedAttempts: 1,
+			expectedStatus:   http.StatusServiceUnavailable,
+		},
+		{
+			name:             "zero policy disables retries",
+			method:           http.MethodGet,
+			policy:           tools.RetryPolicy{},
+			script:           []scriptedResponse{{status: 503}, {status: 200}},
+			expectedAttempts: 1,
+			expectedStatus:   http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, attempts := newScriptedClient(tt.policy, tt.script...)
+
+			req, err := http.NewRequest(tt.method, "https://api.example.com/test", nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+
+			resp, err := client.DoRequest(req)
+			if *attempts != tt.expectedAttempts {
+				t.Errorf("Attempts = %d, want %d", *attempts, tt.expectedAttempts)
+			}
+
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				if !errors.Is(err, networkErr) {
+					t.Errorf("Error = %v, want wrapped %v", err, networkErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAPIClient_DoRequest_RetryRespectsDeadline(t *testing.T) {
+	t.Parallel()
+
+	policy := tools.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Minute}
+	client, attempts := newScriptedClient(policy, scriptedResponse{status: 503})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.example.com/test", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	start := time.Now()
+	resp, err := client.DoRequest(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("DoRequest() waited %v, should give up when the delay exceeds the deadline", elapsed)
+	}
+	if *attempts != 1 {
+		t.Errorf("Attempts = %d, want 1", *attempts)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+}
+
+func TestAPIClient_DoRequest_RetryStopsOnCancel(t *testing.T) {
+	t.Parallel()
+
+	policy := tools.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Minute}
+	client, _ := newScriptedClient(policy, scriptedResponse{status: 503})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.example.com/test", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	resp, err := client.DoRequest(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("DoRequest() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestNewRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		RetryMaxAttempts: 4,
+		RetryBaseDelay:   time.Second,
+		RetryMaxDelay:    time.Minute,
+		RetryJitter:      0.1,
+	}
+
+	policy := tools.NewRetryPolicy(cfg)
+	expected := tools.RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: time.Minute, Jitter: 0.1}
+	if policy != expected {
+		t.Errorf("NewRetryPolicy() = %+v, want %+v", policy, expected)
+	}
+}
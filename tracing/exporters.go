@@ -0,0 +1,25 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// otlpTracesPath is the OTLP/HTTP path of the traces endpoint.
+const otlpTracesPath = "/v1/traces"
+
+// NewOTLPExporter creates an exporter sending spans to the OpenTelemetry collector at endpoint, e.g.
+// http://localhost:4318, using OTLP/HTTP with protobuf encoding.
+func NewOTLPExporter(ctx context.Context, endpoint string) (sdktrace.SpanExporter, error) {
+	return otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(strings.TrimSuffix(endpoint, "/")+otlpTracesPath))
+}
+
+// NewWriterExporter creates an exporter writing spans to w as JSON, for local debugging.
+func NewWriterExporter(w io.Writer) (sdktrace.SpanExporter, error) {
+	return stdouttrace.New(stdouttrace.WithWriter(w))
+}
@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys, following the OpenTelemetry semantic conventions.
+const (
+	attrMethodName = "mcp.method.name"
+	attrSessionID  = "mcp.session.id"
+	attrToolName   = "gen_ai.tool.name"
+)
+
+// Middleware starts a server span for each MCP request. Clients can continue their own trace by
+// sending a W3C traceparent, and optionally a tracestate, in the _meta of the request; the HTTP
+// headers cannot be used, since the MCP SDK does not pass them on to request handlers.
+func (t *Tracer) Middleware(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
+	return func(ctx context.Context, ss *mcp.ServerSession, method string, params mcp.Params) (mcp.Result, error) {
+		if strings.HasPrefix(method, "notifications/") {
+			return next(ctx, ss, method, params)
+		}
+
+		if params != nil {
+			ctx = contextWithMeta(ctx, params.GetMeta())
+		}
+
+		name := method
+		attributes := []attribute.KeyValue{attribute.String(attrMethodName, method)}
+		if p, ok := params.(*mcp.CallToolParamsFor[json.RawMessage]); ok {
+			name += " " + p.Name
+			attributes = append(attributes, attribute.String(attrToolName, p.Name))
+		}
+		if ss != nil && ss.ID() != "" {
+			attributes = append(attributes, attribute.String(attrSessionID, ss.ID()))
+		}
+
+		ctx, span := t.Start(ctx, name, trace.SpanKindServer, attributes...)
+		defer span.End()
+
+		result, err := next(ctx, ss, method, params)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return result, err
+	}
+}
+
+// ToolHandler wraps the handler of the named tool with a span covering its execution. Tool results
+// reporting an error mark the span as failed.
+func ToolHandler[In, Out any](t *Tracer, name string, handler mcp.ToolHandlerFor[In, Out]) mcp.ToolHandlerFor[In, Out] {
+	if t == nil {
+		return handler
+	}
+
+	return func(ctx context.Context, ss *mcp.ServerSession,
+		params *mcp.CallToolParamsFor[In]) (*mcp.CallToolResultFor[Out], error) {
+		ctx, span := t.Start(ctx, "execute_tool "+name, trace.SpanKindInternal, attribute.String(attrToolName, name))
+		defer span.End()
+
+		result, err := handler(ctx, ss, params)
+		switch {
+		case err != nil:
+			span.SetStatus(codes.Error, err.Error())
+		case result != nil && result.IsError:
+			span.SetStatus(codes.Error, toolErrorMessage(result.Content))
+		}
+		return result, err
+	}
+}
+
+// toolErrorMessage returns the text of a tool error result.
+func toolErrorMessage(content []mcp.Content) string {
+	for _, c := range content {
+		if text, ok := c.(*mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return "tool reported an error"
+}
@@ -0,0 +1,114 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rameshsunkara/go-mcp-example/tracing"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer_Middleware(t *testing.T) {
+	t.Parallel()
+
+	exporter := newRecordingExporter()
+	tracer := tracing.NewTracer(exporter, tracing.Resource{ServiceName: "test"})
+
+	var active trace.SpanContext
+	handler := tracer.Middleware(func(ctx context.Context, _ *mcp.ServerSession, method string,
+		_ mcp.Params) (mcp.Result, error) {
+		if method == "tools/call" {
+			active = trace.SpanContextFromContext(ctx)
+			return nil, errors.New("unknown tool")
+		}
+		return &mcp.ListToolsResult{}, nil
+	})
+
+	call := &mcp.CallToolParamsFor[json.RawMessage]{Name: "get_report"}
+	call.SetMeta(map[string]any{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"})
+	_, _ = handler(context.Background(), nil, "tools/call", call)
+	_, _ = handler(context.Background(), nil, "tools/list", nil)
+	_, _ = handler(context.Background(), nil, "notifications/initialized", nil)
+
+	spans := exporter.exported(t, tracer)
+	if len(spans) != 2 {
+		t.Fatalf("Exported %d spans, want 2: %v", len(spans), spans)
+	}
+
+	span, ok := spans["tools/call get_report"]
+	if !ok || !span.SpanContext.Equal(active) {
+		t.Fatalf("Tool call span is not active in the handler: %v", spans)
+	}
+	if span.SpanKind != trace.SpanKindServer || span.Parent.SpanID() != remoteParent.SpanID() {
+		t.Errorf("Tool call span is not a server span continuing the client trace")
+	}
+	if span.Status.Code != codes.Error || span.Status.Description != "unknown tool" {
+		t.Errorf("Tool call span status = %+v, want error unknown tool", span.Status)
+	}
+	if list, ok := spans["tools/list"]; !ok || list.Parent.IsValid() {
+		t.Error("List tools span is not the root of a new trace")
+	}
+}
+
+func TestToolHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		result  *mcp.CallToolResultFor[any]
+		err     error
+		status  codes.Code
+		message string
+	}{
+		{
+			name:   "success",
+			result: &mcp.CallToolResultFor[any]{},
+			status: codes.Unset,
+		},
+		{
+			name: "tool error",
+			result: &mcp.CallToolResultFor[any]{
+				IsError: true,
+				Content: []mcp.Content{&mcp.TextContent{Text: "invalid report"}},
+			},
+			status:  codes.Error,
+			message: "invalid report",
+		},
+		{
+			name:    "error",
+			err:     errors.New("upstream unavailable"),
+			status:  codes.Error,
+			message: "upstream unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			exporter := newRecordingExporter()
+			tracer := tracing.NewTracer(exporter, tracing.Resource{ServiceName: "test"})
+			handler := tracing.ToolHandler(tracer, "get_report", func(context.Context, *mcp.ServerSession,
+				*mcp.CallToolParamsFor[any]) (*mcp.CallToolResultFor[any], error) {
+				return tt.result, tt.err
+			})
+
+			result, err := handler(context.Background(), nil, &mcp.CallToolParamsFor[any]{})
+			if result != tt.result || !errors.Is(err, tt.err) {
+				t.Errorf("ToolHandler() changed the result to %v, %v", result, err)
+			}
+
+			span, ok := exporter.exported(t, tracer)["execute_tool get_report"]
+			if !ok {
+				t.Fatal("ToolHandler() exported no span")
+			}
+			if span.Status.Code != tt.status || span.Status.Description != tt.message {
+				t.Errorf("Span status = %+v, want %v %q", span.Status, tt.status, tt.message)
+			}
+		})
+	}
+}
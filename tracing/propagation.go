@@ -0,0 +1,28 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// propagator reads and writes the W3C Trace Context traceparent and tracestate fields.
+var propagator = propagation.TraceContext{}
+
+// Inject sets the traceparent header for the active span of ctx, if any.
+func Inject(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// contextWithMeta returns a context whose next span continues the trace carried by the _meta of an
+// MCP request, if any.
+func contextWithMeta(ctx context.Context, meta map[string]any) context.Context {
+	carrier := propagation.MapCarrier{}
+	for _, field := range propagator.Fields() {
+		if value, ok := meta[field].(string); ok {
+			carrier.Set(field, value)
+		}
+	}
+	return propagator.Extract(ctx, carrier)
+}
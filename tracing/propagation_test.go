@@ -0,0 +1,30 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/rameshsunkara/go-mcp-example/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInject(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	tracing.Inject(context.Background(), header)
+	if got := header.Get("traceparent"); got != "" {
+		t.Errorf("Inject() without a span set traceparent %q", got)
+	}
+
+	tracer := tracing.NewTracer(newRecordingExporter(), tracing.Resource{ServiceName: "test"})
+	ctx, span := tracer.Start(context.Background(), "request", trace.SpanKindClient)
+	defer span.End()
+
+	tracing.Inject(ctx, header)
+	sc := span.SpanContext()
+	if got, want := header.Get("traceparent"), "00-"+sc.TraceID().String()+"-"+sc.SpanID().String()+"-01"; got != want {
+		t.Errorf("Inject() traceparent = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationScope names the instrumentation that produced the spans.
+const instrumentationScope = "github.com/rameshsunkara/go-mcp-example"
+
+// Resource describes the service emitting the spans.
+type Resource struct {
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Tracer starts spans with an OpenTelemetry tracer provider, which exports them in batches in the
+// background. A nil *Tracer starts no-op spans, so that components can take one optionally.
+type Tracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+}
+
+// NewTracer creates a Tracer that exports the spans of resource with exporter until Shutdown is called.
+func NewTracer(exporter sdktrace.SpanExporter, resource Resource) *Tracer {
+	attributes := []attribute.KeyValue{semconv.ServiceName(resource.ServiceName)}
+	if resource.ServiceVersion != "" {
+		attributes = append(attributes, semconv.ServiceVersion(resource.ServiceVersion))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(sdkresource.NewWithAttributes(semconv.SchemaURL, attributes...)),
+	)
+	return &Tracer{provider: provider, tracer: provider.Tracer(instrumentationScope)}
+}
+
+// Start starts a span that is a child of the active span of ctx, or of its remote parent, and
+// returns a context with the new span active. Without a parent the span starts a new trace.
+func (t *Tracer) Start(ctx context.Context, name string, kind trace.SpanKind,
+	attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	if t == nil {
+		return ctx, noop.Span{}
+	}
+	return t.tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attributes...))
+}
+
+// Shutdown exports the buffered spans and stops the Tracer. Spans ended afterwards are dropped.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	return t.provider.Shutdown(ctx)
+}
@@ -0,0 +1,227 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rameshsunkara/go-mcp-example/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// remoteParent is the span context of a client span in another process.
+var remoteParent = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID:    mustTraceID("4bf92f3577b34da6a3ce929d0e0e4736"),
+	SpanID:     mustSpanID("00f067aa0ba902b7"),
+	TraceFlags: trace.FlagsSampled,
+	Remote:     true,
+})
+
+func mustTraceID(s string) trace.TraceID {
+	id, err := trace.TraceIDFromHex(s)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+func mustSpanID(s string) trace.SpanID {
+	id, err := trace.SpanIDFromHex(s)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// recordingExporter records the exported spans and keeps them after the tracer shuts down.
+type recordingExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func newRecordingExporter() recordingExporter {
+	return recordingExporter{InMemoryExporter: tracetest.NewInMemoryExporter()}
+}
+
+func (recordingExporter) Shutdown(context.Context) error { return nil }
+
+// exported shuts the tracer down and returns the exported spans by name.
+func (e recordingExporter) exported(t *testing.T, tracer *tracing.Tracer) map[string]tracetest.SpanStub {
+	t.Helper()
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() unexpected error: %v", err)
+	}
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range e.GetSpans() {
+		spans[span.Name] = span
+	}
+	return spans
+}
+
+func TestTracer_Start(t *testing.T) {
+	t.Parallel()
+
+	exporter := newRecordingExporter()
+	tracer := tracing.NewTracer(exporter, tracing.Resource{ServiceName: "test"})
+
+	ctx, parent := tracer.Start(trace.ContextWithRemoteSpanContext(context.Background(), remoteParent),
+		"parent", trace.SpanKindServer)
+	_, child := tracer.Start(ctx, "child", trace.SpanKindClient, attribute.Int("attempt", 1))
+	child.SetStatus(codes.Error, "failed")
+	child.End()
+	parent.End()
+	parent.End()
+	_, root := tracer.Start(context.Background(), "root", trace.SpanKindInternal)
+	root.End()
+
+	spans := exporter.exported(t, tracer)
+	if len(spans) != 3 {
+		t.Fatalf("Exported %d spans, want 3", len(spans))
+	}
+
+	if spans["parent"].SpanContext.TraceID() != remoteParent.TraceID() ||
+		spans["parent"].Parent.SpanID() != remoteParent.SpanID() {
+		t.Errorf("Parent span does not continue the remote trace: %+v", spans["parent"].SpanContext)
+	}
+	if spans["child"].SpanContext.TraceID() != remoteParent.TraceID() ||
+		spans["child"].Parent.SpanID() != spans["parent"].SpanContext.SpanID() {
+		t.Errorf("Child span is not a child of the parent span")
+	}
+	if status := spans["child"].Status; status.Code != codes.Error || status.Description != "failed" {
+		t.Errorf("Child span status = %+v, want error failed", status)
+	}
+	if attributes := spans["child"].Attributes; len(attributes) != 1 || attributes[0].Value.AsInt64() != 1 {
+		t.Errorf("Child span attributes = %v, want attempt=1", attributes)
+	}
+	if spans["root"].Parent.IsValid() || spans["root"].SpanContext.TraceID() == remoteParent.TraceID() {
+		t.Error("Root span is not the root of a new trace")
+	}
+	if service, _ := spans["root"].Resource.Set().Value("service.name"); service.AsString() != "test" {
+		t.Errorf("Span service name = %q, want test", service.AsString())
+	}
+}
+
+func TestTracer_Nil(t *testing.T) {
+	t.Parallel()
+
+	// A nil tracer starts no-op spans, which ignore all calls
+	var tracer *tracing.Tracer
+	ctx, span := tracer.Start(context.Background(), "request", trace.SpanKindServer)
+	if span.IsRecording() || span.SpanContext().IsValid() || trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		t.Fatal("Start() on a nil tracer started a span")
+	}
+	span.SetAttributes(attribute.String("key", "value"))
+	span.SetStatus(codes.Error, "failed")
+	span.End()
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() on a nil tracer unexpected error: %v", err)
+	}
+}
+
+func TestWriterExporter(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	exporter, err := tracing.NewWriterExporter(&out)
+	if err != nil {
+		t.Fatalf("NewWriterExporter() unexpected error: %v", err)
+	}
+	tracer := tracing.NewTracer(exporter, tracing.Resource{ServiceName: "test", ServiceVersion: "1.0"})
+	ctx, parent := tracer.Start(context.Background(), "parent", trace.SpanKindServer)
+	_, child := tracer.Start(ctx, "child", trace.SpanKindClient, attribute.String("url.path", "/reports/realtime/data"))
+	child.End()
+	parent.End()
+	if err = tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() unexpected error: %v", err)
+	}
+
+	type spanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	type exportedSpan struct {
+		Name        string
+		SpanContext spanContext
+		Parent      spanContext
+	}
+	var spans []exportedSpan
+	decoder := json.NewDecoder(&out)
+	for {
+		var span exportedSpan
+		if err = decoder.Decode(&span); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("Exported invalid JSON: %v", err)
+		}
+		spans = append(spans, span)
+	}
+
+	if len(spans) != 2 || spans[0].Name != "child" || spans[1].Name != "parent" {
+		t.Fatalf("Exported spans = %+v, want child and parent", spans)
+	}
+	if spans[0].Parent != spans[1].SpanContext {
+		t.Errorf("Exported child %+v is not a child of %+v", spans[0], spans[1])
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu          sync.Mutex
+		path        string
+		contentType string
+		received    int
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		path, contentType, received = r.URL.Path, r.Header.Get("Content-Type"), len(body)
+	}))
+	defer collector.Close()
+
+	exporter, err := tracing.NewOTLPExporter(context.Background(), collector.URL+"/")
+	if err != nil {
+		t.Fatalf("NewOTLPExporter() unexpected error: %v", err)
+	}
+	tracer := tracing.NewTracer(exporter, tracing.Resource{ServiceName: "test"})
+	_, span := tracer.Start(context.Background(), "request", trace.SpanKindServer)
+	span.End()
+	if err = tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if path != "/v1/traces" || contentType != "application/x-protobuf" || received == 0 {
+		t.Errorf("Collector received %d bytes of %q at %q, want protobuf spans at /v1/traces",
+			received, contentType, path)
+	}
+}
+
+func TestOTLPExporter_Error(t *testing.T) {
+	t.Parallel()
+
+	collector := httptest.NewServer(http.NotFoundHandler())
+	defer collector.Close()
+
+	exporter, err := tracing.NewOTLPExporter(context.Background(), collector.URL)
+	if err != nil {
+		t.Fatalf("NewOTLPExporter() unexpected error: %v", err)
+	}
+	spans := tracetest.SpanStubs{{Name: "request"}}.Snapshots()
+	if err = exporter.ExportSpans(context.Background(), spans); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("ExportSpans() to a failing collector error = %v, want status 404", err)
+	}
+}